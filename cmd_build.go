@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"resume-builder/utils"
+)
+
+func runBuild(args []string) int {
+	fs := newFlagSet("build")
+	inputs := addInputFlags(fs)
+	outputFile := fs.String("output", "resume.pdf", "Output file (- for stdout; default resume.<format>)")
+	formatName := fs.String("format", "pdf", "Output format: pdf, html, txt, docx or tex")
+	templateName := fs.String("template", "template-1", "Template to use")
+	watch := fs.Bool("watch", false, "Rebuild whenever the inputs change")
+	manifestFile := fs.String("manifest", "", "Build every target listed in a manifest file")
+	dryRun := fs.Bool("dry-run", false, "Lay out the resume and print a report instead of writing the PDF")
+	pdfa := fs.Bool("pdfa", false, "Write PDF/A-2b: embed every font and add XMP metadata and an sRGB output intent")
+	jobs := fs.Int("jobs", 0, "Maximum concurrent manifest builds (default: manifest jobs, then CPU count)")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	if *manifestFile != "" {
+		// -format, -template and -pdfa given on the command line fill in
+		// for targets that don't set their own
+		var fallback utils.ManifestTarget
+		if flagSet(fs, "format") {
+			if _, err := findOutputFormat(*formatName); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitUsage
+			}
+			if *pdfa && *formatName != "pdf" {
+				fmt.Fprintln(os.Stderr, "-pdfa makes the PDF archival; it can't be used with -format "+*formatName)
+				return exitUsage
+			}
+			fallback.Format = *formatName
+		}
+		if flagSet(fs, "template") {
+			fallback.Template = *templateName
+		}
+		if *pdfa {
+			fallback.Overrides = map[string]interface{}{"pdf.pdfa": true}
+		}
+		return buildManifest(*manifestFile, *jobs, fallback)
+	}
+
+	format, err := findOutputFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if !flagSet(fs, "output") {
+		*outputFile = "resume" + format.extension
+	}
+
+	if *pdfa && format.name != "pdf" {
+		fmt.Fprintln(os.Stderr, "-pdfa makes the PDF archival; it can't be used with -format "+format.name)
+		return exitUsage
+	}
+
+	build := func(cfg *utils.Config, content *utils.Content) error {
+		if *pdfa {
+			cfg.PDF.PDFA = true
+		}
+		return generateOutput(cfg, content, format.name, *templateName, *outputFile)
+	}
+	if *dryRun {
+		if format.name != "pdf" {
+			fmt.Fprintln(os.Stderr, "-dry-run reports the PDF layout; it can't be used with -format "+format.name)
+			return exitUsage
+		}
+		build = func(cfg *utils.Config, content *utils.Content) error {
+			// PDF/A swaps in embedded fonts, which changes the layout
+			if *pdfa {
+				cfg.PDF.PDFA = true
+			}
+			report, err := layoutPDF(cfg, content, *templateName)
+			if err != nil {
+				return err
+			}
+			printLayout(os.Stdout, report)
+			return nil
+		}
+	}
+
+	if *watch {
+		if inputs.readsStdin() || *outputFile == utils.Stdio {
+			fmt.Fprintln(os.Stderr, "-watch needs files: it can't read stdin or write stdout")
+			return exitUsage
+		}
+		target := *outputFile
+		if *dryRun {
+			target = "layout report"
+		}
+		return watchInputs(inputs, target, build)
+	}
+
+	// Load config and content
+	cfg, content, err := inputs.load()
+	if err != nil {
+		reportError("Error", err)
+		return exitCodeFor(err)
+	}
+
+	// Render the resume in the chosen format
+	err = build(cfg, content)
+	if err != nil {
+		reportError("Error generating "+strings.ToUpper(format.name), err)
+		return exitCodeFor(err)
+	}
+
+	if *dryRun {
+		return exitOK
+	}
+	fmt.Fprintf(statusOutput(*outputFile), "Resume %s generated: %s\n", strings.ToUpper(format.name), outputName(*outputFile))
+	return exitOK
+}
+
+// flagSet reports whether a flag was given on the command line
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
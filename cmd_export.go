@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"resume-builder/utils"
+)
+
+// exporter converts content into a document for another format. Anything
+// that can't be carried over comes back as warnings.
+type exporter struct {
+	name    string
+	summary string
+	convert func(content *utils.Content, src *utils.Source) (interface{}, utils.Problems)
+}
+
+var exporters = []exporter{
+	{"jsonresume", "JSON Resume document (jsonresume.org)", func(content *utils.Content, src *utils.Source) (interface{}, utils.Problems) {
+		return utils.ExportJSONResume(content, src)
+	}},
+}
+
+func runExport(args []string) int {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		printExportUsage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	var exp *exporter
+	for i := range exporters {
+		if exporters[i].name == args[0] {
+			exp = &exporters[i]
+		}
+	}
+	if exp == nil {
+		fmt.Fprintf(os.Stderr, "Unknown export format: %s\n\n", args[0])
+		printExportUsage()
+		return exitUsage
+	}
+
+	fs := newFlagSet("export " + exp.name)
+	inputFile := fs.String("input", "cnt.json", "Resume content file (JSON, YAML or TOML, - for stdin)")
+	outputFile := fs.String("output", "resume.json", "File to write (- for stdout)")
+	force := fs.Bool("force", false, "Overwrite the output file if it exists")
+	if code, stop := parseFlags(fs, args[1:]); stop {
+		return code
+	}
+
+	content, src, err := utils.LoadContentSource(*inputFile)
+	if err != nil {
+		reportError("Error loading content", err)
+		return exitCodeFor(err)
+	}
+
+	doc, dropped := exp.convert(content, src)
+	for _, problem := range dropped {
+		utils.Warn(problem)
+	}
+
+	err = utils.SaveJSON(*outputFile, doc, *force)
+	if errors.Is(err, os.ErrExist) {
+		reportError("Error", fmt.Errorf("%w (use -force to overwrite)", err))
+		return exitIO
+	}
+	if err != nil {
+		reportError("Error", err)
+		return exitCodeFor(err)
+	}
+
+	status := statusOutput(*outputFile)
+	fmt.Fprintf(status, "Exported %s to %s\n", utils.DisplayName(*inputFile), outputName(*outputFile))
+	if len(dropped) > 0 {
+		fmt.Fprintf(status, "%d item(s) could not be exported; see the warnings above\n", len(dropped))
+	}
+	return exitOK
+}
+
+func printExportUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: resume-builder export <format> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Formats:")
+	for _, exp := range exporters {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", exp.name, exp.summary)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"resume-builder/utils"
+)
+
+func runIcons(args []string) int {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		fmt.Fprintln(os.Stderr, "Usage: resume-builder icons <list|build|clean> [flags]")
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	action := args[0]
+	fs := newFlagSet("icons " + action)
+	configFile := fs.String("config", "config.json", "Layout config file (JSON, YAML or TOML)")
+	force := fs.Bool("force", false, "Render icons again even when cached (build only)")
+	if code, stop := parseFlags(fs, args[1:]); stop {
+		return code
+	}
+
+	cfg, err := utils.LoadConfig(*configFile)
+	if err != nil {
+		reportError("Error loading config", err)
+		return exitCodeFor(err)
+	}
+
+	switch action {
+	case "list":
+		for _, entry := range utils.ListIconCache(&cfg.Icons, cfg.Colors) {
+			status := "missing"
+			if entry.Cached {
+				status = "cached"
+			}
+			fmt.Printf("%-14s %-30s %-8s %s\n", entry.Name, entry.File, status, entry.Path)
+		}
+	case "build":
+		entries, err := utils.BuildIconCache(&cfg.Icons, cfg.Colors, *force)
+		for _, entry := range entries {
+			if entry.Cached {
+				fmt.Printf("%-14s %s\n", entry.Name, entry.Path)
+			}
+		}
+		if err != nil {
+			reportError("Error building icons", err)
+			return exitCodeFor(err)
+		}
+	case "clean":
+		removed, err := utils.CleanIconCache(&cfg.Icons)
+		for _, path := range removed {
+			fmt.Printf("Removed %s\n", path)
+		}
+		if err != nil {
+			reportError("Error cleaning icons", err)
+			return exitCodeFor(err)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown icons action: %s\n", action)
+		return exitUsage
+	}
+
+	return exitOK
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"resume-builder/utils"
+)
+
+// importer converts a resume kept in another format into content
+type importer struct {
+	name    string
+	summary string
+	load    func(filename string) (*utils.Content, error)
+	// loadWithConfig replaces load for formats that need the layout
+	// config, such as vCard for the icon names
+	loadWithConfig func(filename string, cfg *utils.Config) (*utils.Content, error)
+}
+
+var importers = []importer{
+	{name: "jsonresume", summary: "JSON Resume document (jsonresume.org)", load: utils.ImportJSONResume},
+	{name: "markdown", summary: "Markdown resume", load: utils.ImportMarkdown},
+	{name: "linkedin", summary: "LinkedIn data export archive (.zip)", load: utils.ImportLinkedIn},
+	{name: "europass", summary: "Europass CV (SkillsPassport XML)", load: utils.ImportEuropass},
+	{name: "vcard", summary: "Personal info and contact fields from a vCard (.vcf)", loadWithConfig: importVCard},
+}
+
+func importVCard(filename string, cfg *utils.Config) (*utils.Content, error) {
+	return utils.ImportVCard(filename, cfg.Icons.Mappings)
+}
+
+func runImport(args []string) int {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		printImportUsage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	var imp *importer
+	for i := range importers {
+		if importers[i].name == args[0] {
+			imp = &importers[i]
+		}
+	}
+	if imp == nil {
+		fmt.Fprintf(os.Stderr, "Unknown import format: %s\n\n", args[0])
+		printImportUsage()
+		return exitUsage
+	}
+
+	fs := newFlagSet("import " + imp.name)
+	outputFile := fs.String("output", "cnt.json", "Content file to write (- for stdout)")
+	force := fs.Bool("force", false, "Overwrite the output file if it exists")
+	merge := fs.Bool("merge", false, "Merge into the existing output file, keeping what the import doesn't replace")
+	var configFile *string
+	if imp.loadWithConfig != nil {
+		configFile = fs.String("config", "config.json", "Layout config file (JSON, YAML or TOML)")
+	}
+	if code, stop := parseFlags(fs, args[1:]); stop {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: resume-builder import %s [flags] <file>\n", imp.name)
+		return exitUsage
+	}
+	if *merge && *outputFile == utils.Stdio {
+		fmt.Fprintln(os.Stderr, "-merge needs an output file to merge into")
+		return exitUsage
+	}
+
+	var content *utils.Content
+	var err error
+	if imp.loadWithConfig != nil {
+		var cfg *utils.Config
+		cfg, err = utils.LoadConfig(*configFile)
+		if err != nil {
+			reportError("Error loading config", err)
+			return exitCodeFor(err)
+		}
+		content, err = imp.loadWithConfig(fs.Arg(0), cfg)
+	} else {
+		content, err = imp.load(fs.Arg(0))
+	}
+	if err != nil {
+		reportError("Error importing "+utils.DisplayName(fs.Arg(0)), err)
+		return exitCodeFor(err)
+	}
+
+	verb := "Imported"
+	if *merge {
+		if _, statErr := os.Stat(*outputFile); statErr == nil {
+			existing, err := utils.LoadContent(*outputFile)
+			if err != nil {
+				reportError("Error loading "+*outputFile, err)
+				return exitCodeFor(err)
+			}
+			utils.MergeContent(existing, content)
+			content, verb = existing, "Merged"
+		}
+	}
+
+	err = utils.SaveJSON(*outputFile, content, *force || *merge)
+	if errors.Is(err, os.ErrExist) {
+		reportError("Error", fmt.Errorf("%w (use -force to overwrite)", err))
+		return exitIO
+	}
+	if err != nil {
+		reportError("Error", err)
+		return exitCodeFor(err)
+	}
+
+	fmt.Fprintf(statusOutput(*outputFile), "%s %s into %s\n", verb, utils.DisplayName(fs.Arg(0)), outputName(*outputFile))
+	return exitOK
+}
+
+func printImportUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: resume-builder import <format> [flags] <file>")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Formats:")
+	for _, imp := range importers {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", imp.name, imp.summary)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"resume-builder/utils"
+)
+
+func runInit(args []string) int {
+	fs := newFlagSet("init")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	interactive := fs.Bool("interactive", false, "Prompt for personal info")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	personal := utils.DefaultStarterPersonal()
+	if *interactive {
+		var err error
+		personal, err = promptPersonalInfo(os.Stdin, os.Stdout, personal)
+		if err != nil {
+			reportError("Error", err)
+			return exitCodeFor(err)
+		}
+	}
+
+	created, err := utils.WriteStarterProject(dir, personal, *force)
+	for _, path := range created {
+		fmt.Printf("Created %s\n", path)
+	}
+	if errors.Is(err, os.ErrExist) {
+		err = fmt.Errorf("%w (use -force to overwrite)", err)
+	}
+	if err != nil {
+		reportError("Error", err)
+		return exitCodeFor(err)
+	}
+
+	fmt.Printf("\nBuild it with:\n  resume-builder build -config %s -input %s\n",
+		filepath.Join(dir, utils.StarterConfigFile), filepath.Join(dir, utils.StarterContentFile))
+	return exitOK
+}
+
+// promptPersonalInfo asks for each PersonalInfo field, keeping the
+// default when the answer is empty
+func promptPersonalInfo(in io.Reader, out io.Writer, defaults utils.PersonalInfo) (utils.PersonalInfo, error) {
+	reader := bufio.NewReader(in)
+	info := defaults
+
+	fields := []struct {
+		label string
+		value *string
+	}{
+		{"Name", &info.Name},
+		{"Email", &info.Email},
+		{"Phone", &info.Phone},
+		{"Address", &info.Address},
+		{"Website URL", &info.Website},
+		{"GitHub URL", &info.GitHub},
+		{"LinkedIn URL", &info.LinkedIn},
+	}
+
+	for _, field := range fields {
+		if *field.value != "" {
+			fmt.Fprintf(out, "%s [%s]: ", field.label, *field.value)
+		} else {
+			fmt.Fprintf(out, "%s (optional): ", field.label)
+		}
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return info, err
+		}
+		if answer = strings.TrimSpace(answer); answer != "" {
+			*field.value = answer
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return info, nil
+}
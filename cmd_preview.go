@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"resume-builder/templates"
+	"resume-builder/utils"
+)
+
+func runPreview(args []string) int {
+	fs := newFlagSet("preview")
+	inputs := addInputFlags(fs)
+	templateName := fs.String("template", "template-1", "Template to use")
+	outputFile := fs.String("output", filepath.Join(os.TempDir(), "resume-preview.png"), "Preview PNG file; each page gets its number appended")
+	dpi := fs.Float64("dpi", 96, "Resolution of the preview images")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+	if *outputFile == utils.Stdio {
+		fmt.Fprintln(os.Stderr, "preview writes one PNG per page; it can't write to stdout")
+		return exitUsage
+	}
+	if *dpi <= 0 {
+		fmt.Fprintln(os.Stderr, "-dpi must be positive")
+		return exitUsage
+	}
+
+	cfg, content, err := inputs.load()
+	if err != nil {
+		reportError("Error", err)
+		return exitCodeFor(err)
+	}
+
+	pages, err := previewPages(cfg, content, *templateName, *dpi)
+	if err != nil {
+		reportError("Error generating preview", err)
+		return exitCodeFor(err)
+	}
+
+	for i, page := range pages {
+		filename := pageFilename(*outputFile, i+1)
+		if err := writePNG(filename, page); err != nil {
+			reportError("Error writing preview", err)
+			return exitCodeFor(err)
+		}
+		fmt.Printf("Page %d written to %s\n", i+1, filename)
+	}
+	return exitOK
+}
+
+// previewPages lays the resume out like the PDF and draws each page
+func previewPages(cfg *utils.Config, content *utils.Content, templateName string, dpi float64) ([]image.Image, error) {
+	mrt, err := newDocument(cfg, content)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := templates.NewLayoutRecorder(mrt)
+	if err := buildTemplate(recorder, cfg, content, templateName); err != nil {
+		return nil, err
+	}
+
+	return recorder.Rasterize(dpi)
+}
+
+// pageFilename numbers the page: resume-preview.png becomes
+// resume-preview-2.png for page 2
+func pageFilename(outputFile string, page int) string {
+	ext := filepath.Ext(outputFile)
+	if !strings.EqualFold(ext, ".png") {
+		ext = ""
+	}
+	return fmt.Sprintf("%s-%d.png", strings.TrimSuffix(outputFile, ext), page)
+}
+
+func writePNG(filename string, img image.Image) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"resume-builder/utils"
+)
+
+func runServe(args []string) int {
+	fs := newFlagSet("serve")
+	inputs := addInputFlags(fs)
+	templateName := fs.String("template", "template-1", "Template to use")
+	addr := fs.String("addr", "localhost:8080", "Address to listen on")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	if inputs.readsStdin() {
+		fmt.Fprintln(os.Stderr, "serve rebuilds on every change, so it can't read stdin")
+		return exitUsage
+	}
+
+	srv := &previewServer{
+		inputs:       inputs,
+		templateName: *templateName,
+		clients:      make(map[chan struct{}]bool),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher := utils.NewFileWatcher(srv.watchPaths())
+	go watcher.Watch(ctx, watchInterval, watchDebounce, func() {
+		watcher.SetPaths(srv.watchPaths())
+		srv.broadcastReload()
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", srv.handleIndex)
+	mux.HandleFunc("/resume.pdf", srv.handlePDF)
+	mux.HandleFunc("/events", srv.handleEvents)
+
+	httpServer := &http.Server{Addr: *addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving resume preview at http://%s (Ctrl+C to stop)\n", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		reportError("Error", err)
+		return exitCodeFor(err)
+	}
+
+	return exitOK
+}
+
+// previewServer renders the resume on demand and tells open pages to
+// reload over Server-Sent Events when the inputs change
+type previewServer struct {
+	inputs       inputFlags
+	templateName string
+
+	mu      sync.Mutex
+	pdf     []byte
+	clients map[chan struct{}]bool
+}
+
+// build renders the current inputs and caches the PDF for /resume.pdf
+func (s *previewServer) build() error {
+	cfg, content, err := s.inputs.load()
+	if err != nil {
+		return err
+	}
+
+	data, err := renderPDF(cfg, content, s.templateName)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.pdf = data
+	s.mu.Unlock()
+	return nil
+}
+
+// watchPaths lists the files the preview depends on. Inputs that fail to
+// load still have their own files watched.
+func (s *previewServer) watchPaths() []string {
+	cfg, _ := utils.LoadConfig(*s.inputs.config)
+	content, _ := utils.LoadContent(*s.inputs.input)
+	return utils.WatchPaths(*s.inputs.config, *s.inputs.input, cfg, content)
+}
+
+func (s *previewServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := previewPage{Version: time.Now().UnixNano()}
+	if err := s.build(); err != nil {
+		page.Errors = errorLines(err)
+		fmt.Printf("[%s] Build failed\n", time.Now().Format("15:04:05"))
+		reportError("Error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	previewTemplate.Execute(w, page)
+}
+
+func (s *previewServer) handlePDF(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data := s.pdf
+	s.mu.Unlock()
+
+	if data == nil {
+		if err := s.build(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.mu.Lock()
+		data = s.pdf
+		s.mu.Unlock()
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(data)
+}
+
+func (s *previewServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	reload := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[reload] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, reload)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-reload:
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *previewServer) broadcastReload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Printf("[%s] Inputs changed, reloading %d page(s)\n", time.Now().Format("15:04:05"), len(s.clients))
+	for client := range s.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// errorLines splits validation problems into one line each
+func errorLines(err error) []string {
+	var problems utils.Problems
+	if errors.As(err, &problems) {
+		lines := make([]string, len(problems))
+		for i, problem := range problems {
+			lines[i] = problem.Error()
+		}
+		return lines
+	}
+	return []string{err.Error()}
+}
+
+type previewPage struct {
+	Version int64
+	Errors  []string
+}
+
+var previewTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Resume preview</title>
+<style>
+  html, body { margin: 0; height: 100%; background: #525659; font-family: sans-serif; }
+  iframe { border: 0; width: 100%; height: 100%; }
+  .overlay { position: fixed; inset: 0; background: rgba(20, 20, 20, 0.92); color: #f8f8f2; padding: 2em; overflow: auto; }
+  .overlay h1 { color: #ff6b6b; font-size: 1.3em; margin-top: 0; }
+  .overlay pre { font-size: 0.95em; white-space: pre-wrap; line-height: 1.5; }
+</style>
+</head>
+<body>
+{{if .Errors}}
+<div class="overlay">
+  <h1>Build failed</h1>
+  <pre>{{range .Errors}}{{.}}
+{{end}}</pre>
+  <p>Fix the problems above and save; this page reloads automatically.</p>
+</div>
+{{else}}
+<iframe src="/resume.pdf?v={{.Version}}"></iframe>
+{{end}}
+<script>
+  new EventSource("/events").addEventListener("reload", function () {
+    window.location.reload();
+  });
+</script>
+</body>
+</html>
+`))
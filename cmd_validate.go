@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"resume-builder/utils"
+)
+
+func runValidate(args []string) int {
+	fs := newFlagSet("validate")
+	inputs := addInputFlags(fs)
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	_, _, err := inputs.load()
+	if err != nil {
+		reportError("Error", err)
+
+		var problems utils.Problems
+		if errors.As(err, &problems) && !jsonDiagnostics() {
+			fmt.Fprintf(os.Stderr, "%d problem(s) found\n", len(problems))
+		}
+		return exitCodeFor(err)
+	}
+
+	fmt.Printf("%s and %s are valid\n", utils.DisplayName(*inputs.config), utils.DisplayName(*inputs.input))
+	return exitOK
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+	"sync"
+
+	"resume-builder/utils"
+)
+
+// diagnosticsFormat is set by the -diagnostics flag every command shares
+var diagnosticsFormat = "text"
+
+var (
+	diagnosticsMu      sync.Mutex
+	diagnosticsEncoder = json.NewEncoder(os.Stderr)
+)
+
+// diagnostic is one JSON Lines record written to stderr
+type diagnostic struct {
+	utils.Problem
+	Target string `json:"target,omitempty"`
+}
+
+// renderError marks a failure inside the template or PDF generation, as
+// opposed to bad input or a file that couldn't be read or written
+type renderError struct {
+	err error
+}
+
+func (e renderError) Error() string { return e.err.Error() }
+func (e renderError) Unwrap() error { return e.err }
+
+func addDiagnosticsFlag(fs *flag.FlagSet) {
+	fs.StringVar(&diagnosticsFormat, "diagnostics", "text", "Error and warning output: text or json (JSON Lines on stderr)")
+}
+
+// setupDiagnostics routes warnings for the chosen format
+func setupDiagnostics() error {
+	switch diagnosticsFormat {
+	case "text":
+		return nil
+	case "json":
+		utils.SetWarningHandler(func(p utils.Problem) {
+			emitDiagnostic(diagnostic{Problem: p})
+		})
+		return nil
+	default:
+		return fmt.Errorf("invalid -diagnostics %q (want text or json)", diagnosticsFormat)
+	}
+}
+
+func jsonDiagnostics() bool {
+	return diagnosticsFormat == "json"
+}
+
+func emitDiagnostic(d diagnostic) {
+	diagnosticsMu.Lock()
+	defer diagnosticsMu.Unlock()
+	diagnosticsEncoder.Encode(d)
+}
+
+// reportError logs err, printing each validation problem on its own line
+func reportError(prefix string, err error) {
+	reportTargetError("", prefix, err)
+}
+
+// reportTargetError reports err for one manifest target
+func reportTargetError(target, prefix string, err error) {
+	if jsonDiagnostics() {
+		for _, problem := range problemsFor(err) {
+			emitDiagnostic(diagnostic{Problem: problem, Target: target})
+		}
+		return
+	}
+
+	if target != "" {
+		fmt.Fprintf(os.Stderr, "\n%s:\n", target)
+	}
+	var problems utils.Problems
+	if errors.As(err, &problems) {
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem.Error())
+		}
+		return
+	}
+	log.Printf("%s: %v", prefix, err)
+}
+
+// problemsFor turns any error into diagnostic records
+func problemsFor(err error) utils.Problems {
+	var problems utils.Problems
+	if errors.As(err, &problems) {
+		return problems
+	}
+	var problem utils.Problem
+	if errors.As(err, &problem) {
+		return utils.Problems{problem}
+	}
+
+	problem = utils.Problem{Severity: utils.SeverityError, Code: "error", Message: err.Error()}
+	var pathErr *fs.PathError
+	var netErr *net.OpError
+	var renderErr renderError
+	switch {
+	case errors.As(err, &renderErr):
+		problem.Code = "render-failed"
+	case errors.As(err, &pathErr):
+		problem.Code = "io-error"
+		problem.File = pathErr.Path
+	case errors.As(err, &netErr):
+		problem.Code = "io-error"
+	}
+	return utils.Problems{problem}
+}
+
+// exitCodeFor picks the exit code that tells input, render and I/O
+// failures apart
+func exitCodeFor(err error) int {
+	var problems utils.Problems
+	var problem utils.Problem
+	var renderErr renderError
+	var pathErr *fs.PathError
+	var netErr *net.OpError
+	switch {
+	case errors.As(err, &problems), errors.As(err, &problem):
+		return exitInput
+	case errors.As(err, &renderErr):
+		return exitRender
+	case errors.As(err, &pathErr), errors.As(err, &netErr):
+		return exitIO
+	default:
+		return exitError
+	}
+}
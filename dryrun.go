@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"resume-builder/templates"
+	"resume-builder/utils"
+)
+
+// layoutPDF runs the template without saving anything and reports where
+// each row lands
+func layoutPDF(cfg *utils.Config, content *utils.Content, templateName string) (templates.LayoutReport, error) {
+	mrt, err := newDocument(cfg, content)
+	if err != nil {
+		return templates.LayoutReport{}, err
+	}
+
+	recorder := templates.NewLayoutRecorder(mrt)
+	if err := buildTemplate(recorder, cfg, content, templateName); err != nil {
+		return templates.LayoutReport{}, err
+	}
+
+	// Generate anyway so rendering errors show up in a dry run too
+	if _, err := recorder.Generate(); err != nil {
+		return templates.LayoutReport{}, renderError{err}
+	}
+
+	return recorder.Report(), nil
+}
+
+// printLayout writes the layout report, naming each section on its first row
+func printLayout(w io.Writer, report templates.LayoutReport) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SECTION\tROW\tPAGE\tTOP (mm)\tHEIGHT (mm)")
+
+	section := ""
+	for i, row := range report.Rows {
+		label := ""
+		if i == 0 || row.Section != section {
+			section = row.Section
+			label = section
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.1f\t%.1f\n", label, i+1, row.Page, row.Top, row.Height)
+	}
+	table.Flush()
+
+	fmt.Fprintf(w, "\n%d row(s) on %d page(s)\n", len(report.Rows), report.Pages)
+	fmt.Fprintf(w, "%.1fmm of %.1fmm left on page %d\n", report.Remaining, report.PageHeight, report.Pages)
+}
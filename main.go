@@ -1,70 +1,334 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/johnfercher/maroto/v2"
 	"github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontfamily"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
 
-	"resume-builder/utils"
 	"resume-builder/templates"
+	"resume-builder/utils"
+)
+
+// Exit codes shared by every command
+const (
+	exitOK     = 0
+	exitError  = 1
+	exitUsage  = 2
+	exitInput  = 3 // invalid config or content
+	exitRender = 4 // the template or PDF generation failed
+	exitIO     = 5 // a file couldn't be read or written, or serve couldn't listen
 )
 
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"build", "Render the resume PDF", runBuild},
+	{"validate", "Check config and content without rendering", runValidate},
+	{"init", "Scaffold a new resume project", runInit},
+	{"icons", "List, build or clean the icon cache", runIcons},
+	{"preview", "Render a quick preview", runPreview},
+	{"serve", "Serve a live-reloading preview in the browser", runServe},
+	{"import", "Convert a resume from another format into content", runImport},
+	{"export", "Convert content into another resume format", runExport},
+}
+
 func main() {
-	var outputFile = flag.String("output", "resume.pdf", "Output PDF file")
-	var templateName = flag.String("template", "template-1", "Template to use")
-	flag.Parse()
+	args := os.Args[1:]
+
+	// Bare flags keep working as an implicit build
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelpArg(args[0])) {
+		os.Exit(runBuild(args))
+	}
+
+	if isHelpArg(args[0]) || args[0] == "help" {
+		printUsage()
+		os.Exit(exitOK)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			os.Exit(cmd.run(args[1:]))
+		}
+	}
 
-	// Load config and content
-	config, err := utils.LoadConfig("config.json")
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+	printUsage()
+	os.Exit(exitUsage)
+}
+
+func isHelpArg(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: resume-builder <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'resume-builder <command> -h' for command flags.")
+}
+
+// newFlagSet creates a flag set that reports parse errors instead of exiting
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	addDiagnosticsFlag(fs)
+	return fs
+}
+
+// parseFlags parses args and returns the exit code to stop with, if any
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		return exitOK, true
+	}
 	if err != nil {
-		log.Fatalf("Error loading config: %v", err)
+		return exitUsage, true
+	}
+	if err := setupDiagnostics(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage, true
+	}
+	return exitOK, false
+}
+
+type inputFlags struct {
+	input  *string
+	config *string
+}
+
+func addInputFlags(fs *flag.FlagSet) inputFlags {
+	return inputFlags{
+		input:  fs.String("input", "cnt.json", "Resume content file (JSON, YAML or TOML, - for stdin)"),
+		config: fs.String("config", "config.json", "Layout config file (JSON, YAML or TOML, - for stdin)"),
+	}
+}
+
+func (f inputFlags) load() (*utils.Config, *utils.Content, error) {
+	if *f.input == utils.Stdio && *f.config == utils.Stdio {
+		return nil, nil, errors.New("only one of -input and -config can read from stdin")
 	}
+	return utils.LoadInputs(*f.config, *f.input)
+}
+
+// readsStdin reports whether an input comes from stdin, which can only be read once
+func (f inputFlags) readsStdin() bool {
+	return *f.input == utils.Stdio || *f.config == utils.Stdio
+}
 
-	content, err := utils.LoadContent("cnt.json")
+// outputName is how an output file appears in messages
+func outputName(outputFile string) string {
+	if outputFile == utils.Stdio {
+		return "<stdout>"
+	}
+	return outputFile
+}
+
+// statusOutput is where progress messages go: stdout, unless the PDF itself
+// is written there
+func statusOutput(outputFile string) io.Writer {
+	if outputFile == utils.Stdio {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// outputFormat renders the resume as one kind of document
+type outputFormat struct {
+	name      string
+	extension string
+	render    func(cfg *utils.Config, content *utils.Content, templateName string) ([]byte, error)
+}
+
+var outputFormats = []outputFormat{
+	{name: "pdf", extension: ".pdf", render: renderPDF},
+	{name: "html", extension: ".html", render: renderHTML},
+	{name: "txt", extension: ".txt", render: renderText},
+	{name: "docx", extension: ".docx", render: renderDOCX},
+	{name: "tex", extension: ".tex", render: renderLaTeX},
+}
+
+func findOutputFormat(name string) (outputFormat, error) {
+	var names []string
+	for _, format := range outputFormats {
+		if format.name == name {
+			return format, nil
+		}
+		names = append(names, format.name)
+	}
+	return outputFormat{}, fmt.Errorf("unknown output format %q (use %s)", name, strings.Join(names, ", "))
+}
+
+func generatePDF(cfg *utils.Config, content *utils.Content, templateName, filename string) error {
+	return generateOutput(cfg, content, "pdf", templateName, filename)
+}
+
+// generateOutput renders the resume in the named format and writes it to
+// filename, or stdout
+func generateOutput(cfg *utils.Config, content *utils.Content, formatName, templateName, filename string) error {
+	format, err := findOutputFormat(formatName)
 	if err != nil {
-		log.Fatalf("Error loading content: %v", err)
+		return err
 	}
 
-	// Generate PDF using template
-	err = generatePDF(config, content, *templateName, *outputFile)
+	data, err := format.render(cfg, content, templateName)
 	if err != nil {
-		log.Fatalf("Error generating PDF: %v", err)
+		return err
 	}
 
-	fmt.Printf("Resume PDF generated: %s\n", *outputFile)
+	if filename == utils.Stdio {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
 }
 
-func generatePDF(cfg *utils.Config, content *utils.Content, templateName, filename string) error {
-	// Create maroto config
+// renderPDF runs the template and returns the PDF bytes
+func renderPDF(cfg *utils.Config, content *utils.Content, templateName string) ([]byte, error) {
+	mrt, err := newDocument(cfg, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := buildTemplate(mrt, cfg, content, templateName); err != nil {
+		return nil, err
+	}
+
+	document, err := mrt.Generate()
+	if err != nil {
+		return nil, renderError{err}
+	}
+
+	if !cfg.PDF.PDFA {
+		return document.GetBytes(), nil
+	}
+	created, _ := utils.ParseCreationDate(cfg.PDF.CreationDate) // newDocument has checked it
+	if created.IsZero() {
+		created = time.Now()
+	}
+	data, err := convertPDFA(document.GetBytes(), created)
+	if err != nil {
+		return nil, renderError{err}
+	}
+	if err := checkPDFA(data); err != nil {
+		return nil, renderError{err}
+	}
+	return data, nil
+}
+
+// renderHTML renders the standalone HTML version of the template
+func renderHTML(cfg *utils.Config, content *utils.Content, templateName string) ([]byte, error) {
+	if templateName != "template-1" {
+		return nil, fmt.Errorf("unknown template: %s", templateName)
+	}
+
+	data, err := templates.BuildHTML(cfg, content)
+	if err != nil {
+		return nil, renderError{err}
+	}
+	return data, nil
+}
+
+// renderText renders the plain-text version of the template
+func renderText(cfg *utils.Config, content *utils.Content, templateName string) ([]byte, error) {
+	if templateName != "template-1" {
+		return nil, fmt.Errorf("unknown template: %s", templateName)
+	}
+
+	data, err := templates.BuildText(cfg, content)
+	if err != nil {
+		return nil, renderError{err}
+	}
+	return data, nil
+}
+
+// renderDOCX renders the Word version of the template
+func renderDOCX(cfg *utils.Config, content *utils.Content, templateName string) ([]byte, error) {
+	if templateName != "template-1" {
+		return nil, fmt.Errorf("unknown template: %s", templateName)
+	}
+
+	data, err := templates.BuildDOCX(cfg, content)
+	if err != nil {
+		return nil, renderError{err}
+	}
+	return data, nil
+}
+
+// renderLaTeX renders the LaTeX source of the template
+func renderLaTeX(cfg *utils.Config, content *utils.Content, templateName string) ([]byte, error) {
+	if templateName != "template-1" {
+		return nil, fmt.Errorf("unknown template: %s", templateName)
+	}
+
+	data, err := templates.BuildLaTeX(cfg, content)
+	if err != nil {
+		return nil, renderError{err}
+	}
+	return data, nil
+}
+
+// newDocument creates an empty maroto document with the configured page
+// setup and the document info: title, author, subject, skills as keywords
+// and creation date
+func newDocument(cfg *utils.Config, content *utils.Content) (core.Maroto, error) {
+	creationDate, err := utils.ParseCreationDate(cfg.PDF.CreationDate)
+	if err != nil {
+		return nil, err
+	}
+
 	cfgBuilder := config.NewBuilder().
 		WithPageNumber().
 		WithLeftMargin(cfg.PDF.Margins.Left).
 		WithTopMargin(cfg.PDF.Margins.Top).
 		WithRightMargin(cfg.PDF.Margins.Right).
-		WithBottomMargin(cfg.PDF.Margins.Bottom)
-	
-	marotoCfg := cfgBuilder.Build()
-	mrt := maroto.New(marotoCfg)
+		WithBottomMargin(cfg.PDF.Margins.Bottom).
+		WithTitle(templates.DocumentTitle(cfg, content), true).
+		WithAuthor(content.Personal.Name, true).
+		WithSubject(templates.DocumentSubject(cfg, content), true).
+		WithKeywords(templates.DocumentKeywords(content), true).
+		WithCreationDate(creationDate)
+
+	// PDF/A embeds every font, so the core fonts, maroto's default among
+	// them, give way to the Go fonts
+	if cfg.PDF.PDFA {
+		cfgBuilder = cfgBuilder.
+			WithCustomFonts(templates.EmbeddedFonts()).
+			WithDefaultFont(&props.Font{Family: templates.EmbeddedFamily(fontfamily.Arial)})
+	}
+
+	return maroto.New(cfgBuilder.Build()), nil
+}
+
+// buildTemplate adds the rows of the named template to mrt
+func buildTemplate(mrt core.Maroto, cfg *utils.Config, content *utils.Content, templateName string) error {
+	if cfg.PDF.PDFA {
+		cfg = templates.WithEmbeddedFonts(cfg)
+	}
 
-	// Use template-specific logic
 	switch templateName {
 	case "template-1":
-		err := templates.BuildTemplate1(mrt, cfg, content)
-		if err != nil {
-			return err
+		if err := templates.BuildTemplate1(mrt, cfg, content); err != nil {
+			return renderError{err}
 		}
+		return nil
 	default:
 		return fmt.Errorf("unknown template: %s", templateName)
 	}
-
-	// Generate and save
-	document, err := mrt.Generate()
-	if err != nil {
-		return err
-	}
-
-	return document.Save(filename)
-}
\ No newline at end of file
+}
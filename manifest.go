@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"resume-builder/utils"
+)
+
+type targetResult struct {
+	target   utils.ManifestTarget
+	err      error
+	duration time.Duration
+}
+
+// buildManifest renders every manifest target with at most jobs builds
+// running at once and prints a summary table
+func buildManifest(manifestFile string, jobs int, fallback utils.ManifestTarget) int {
+	manifest, err := utils.LoadManifest(manifestFile, fallback)
+	if err != nil {
+		reportError("Error loading manifest", err)
+		return exitCodeFor(err)
+	}
+
+	if jobs <= 0 {
+		jobs = manifest.Jobs
+	}
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	results := make([]targetResult, len(manifest.Targets))
+	slots := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+
+	for i, target := range manifest.Targets {
+		wg.Add(1)
+		go func(i int, target utils.ManifestTarget) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			start := time.Now()
+			err := buildTarget(target)
+			results[i] = targetResult{target: target, err: err, duration: time.Since(start)}
+		}(i, target)
+	}
+	wg.Wait()
+
+	printManifestSummary(results)
+	return manifestExitCode(results)
+}
+
+// manifestExitCode reports the failure kind when every failed target failed
+// the same way, and a plain error when they differ
+func manifestExitCode(results []targetResult) int {
+	code := exitOK
+	for _, result := range results {
+		if result.err == nil {
+			continue
+		}
+		resultCode := exitCodeFor(result.err)
+		if code != exitOK && code != resultCode {
+			return exitError
+		}
+		code = resultCode
+	}
+	return code
+}
+
+func buildTarget(target utils.ManifestTarget) error {
+	cfg, content, err := utils.LoadInputsWithOverrides(target.Config, target.Input, target.Overrides)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target.Output), 0755); err != nil {
+		return err
+	}
+	return generateOutput(cfg, content, target.Format, target.Template, target.Output)
+}
+
+func printManifestSummary(results []targetResult) {
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "TARGET\tSTATUS\tTIME\tOUTPUT")
+
+	failed := 0
+	for _, result := range results {
+		status, detail := "ok", result.target.Output
+		if result.err != nil {
+			failed++
+			status, detail = "FAILED", errorLines(result.err)[0]
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", result.target.Name, status, result.duration.Round(time.Millisecond), detail)
+	}
+	table.Flush()
+
+	fmt.Printf("\n%d built, %d failed\n", len(results)-failed, failed)
+
+	// Full error output after the table, one target at a time
+	for _, result := range results {
+		if result.err != nil {
+			reportTargetError(result.target.Name, "Error", result.err)
+		}
+	}
+}
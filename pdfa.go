@@ -0,0 +1,584 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// PDF/A output is PDF/A-2b: every font embedded, the document info
+// repeated in an XMP packet, and colors tied to sRGB by an output intent.
+// maroto embeds the fonts; convertPDFA adds the rest and checkPDFA checks
+// the result.
+
+const (
+	pdfaPart        = 2
+	pdfaConformance = "B"
+	srgbIdentifier  = "sRGB IEC61966-2.1"
+)
+
+var startXRefPattern = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`)
+
+// pdfcpu would otherwise create and read a config directory. The setting
+// is process-wide, so it's made once here rather than during renders that
+// may run concurrently.
+func init() {
+	api.DisableConfigDir()
+}
+
+// pdfcpuConfig reads and writes PDFs with a classic xref table so an
+// update can be appended by hand
+func pdfcpuConfig() *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.WriteObjectStream = false
+	conf.WriteXRefStream = false
+	return conf
+}
+
+func readPDF(data []byte) (*model.Context, error) {
+	ctx, err := api.ReadContext(bytes.NewReader(data), pdfcpuConfig())
+	if err != nil {
+		return nil, err
+	}
+	if err := api.ValidateContext(ctx); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// convertPDFA turns the PDF maroto generated into PDF/A. pdfcpu rewrites
+// it with a binary header comment and a file ID, printable links and the
+// sRGB output intent. Rewriting resets the producer and dates in the
+// document info, so the dates and the XMP packet that has to repeat them
+// go in an update appended afterwards. The document is dated created, and
+// both dates are written in UTC: maroto's carry no time zone, which
+// readers take as local time while XMP needs an offset.
+func convertPDFA(data []byte, created time.Time) ([]byte, error) {
+	ctx, err := readPDF(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading the PDF for PDF/A: %w", err)
+	}
+	if err := api.OptimizeContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := printAnnotations(ctx); err != nil {
+		return nil, err
+	}
+	if err := addOutputIntent(ctx, created); err != nil {
+		return nil, err
+	}
+
+	var rewritten bytes.Buffer
+	if err := api.WriteContext(ctx, &rewritten); err != nil {
+		return nil, err
+	}
+	return appendXMP(rewritten.Bytes(), map[string]string{
+		"CreationDate": types.DateString(created.UTC()),
+		"ModDate":      types.DateString(time.Now().UTC()),
+	})
+}
+
+// printAnnotations sets the print flag PDF/A requires on every annotation;
+// maroto's links have no flags
+func printAnnotations(ctx *model.Context) error {
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		page, _, _, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return err
+		}
+		annots, err := ctx.DereferenceArray(page["Annots"])
+		if err != nil {
+			return err
+		}
+		for _, annot := range annots {
+			d, err := ctx.DereferenceDict(annot)
+			if err != nil {
+				return err
+			}
+			d.Update("F", types.Integer(4))
+		}
+	}
+	return nil
+}
+
+// addOutputIntent declares the document's RGB colors to be sRGB
+func addOutputIntent(ctx *model.Context, created time.Time) error {
+	profile, err := ctx.NewStreamDictForBuf(srgbProfile(created))
+	if err != nil {
+		return err
+	}
+	profile.InsertInt("N", 3)
+	if err := profile.Encode(); err != nil {
+		return err
+	}
+	profileRef, err := ctx.IndRefForNewObject(*profile)
+	if err != nil {
+		return err
+	}
+
+	root, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+	root.Update("OutputIntents", types.Array{types.Dict{
+		"Type":                      types.Name("OutputIntent"),
+		"S":                         types.Name("GTS_PDFA1"),
+		"OutputConditionIdentifier": types.StringLiteral(srgbIdentifier),
+		"Info":                      types.StringLiteral(srgbIdentifier),
+		"DestOutputProfile":         *profileRef,
+	}})
+	return nil
+}
+
+// appendXMP appends an update that puts the dates, in PDF date format, back
+// into the document info and adds the XMP packet to the catalog
+func appendXMP(data []byte, dates map[string]string) ([]byte, error) {
+	ctx, err := readPDF(data)
+	if err != nil {
+		return nil, err
+	}
+	match := startXRefPattern.FindSubmatch(data)
+	if match == nil || ctx.Info == nil || len(ctx.ID) != 2 {
+		return nil, errors.New("rewritten PDF has no xref, document info or file ID")
+	}
+	infoDict, err := ctx.DereferenceDict(*ctx.Info)
+	if err != nil {
+		return nil, err
+	}
+	for key, date := range dates {
+		if date != "" {
+			infoDict.Update(key, types.StringLiteral(date))
+		}
+	}
+	info, err := documentInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	packet := xmpPacket(info)
+	metadataNr := *ctx.XRefTable.Size
+	rootNr := ctx.Root.ObjectNumber.Value()
+	infoNr := ctx.Info.ObjectNumber.Value()
+	root.Update("Metadata", *types.NewIndirectRef(metadataNr, 0))
+
+	out := bytes.NewBuffer(data)
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		out.WriteString("\n")
+	}
+	metadataOffset := out.Len()
+	fmt.Fprintf(out, "%d 0 obj\n<</Type /Metadata /Subtype /XML /Length %d>>\nstream\n%s\nendstream\nendobj\n", metadataNr, len(packet), packet)
+	rootOffset := out.Len()
+	fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", rootNr, root.PDFString())
+	infoOffset := out.Len()
+	fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", infoNr, infoDict.PDFString())
+
+	// One subsection per object, in object number order
+	offsets := map[int]int{rootNr: rootOffset, infoNr: infoOffset, metadataNr: metadataOffset}
+	xrefOffset := out.Len()
+	out.WriteString("xref\n")
+	for _, nr := range []int{min(rootNr, infoNr), max(rootNr, infoNr), metadataNr} {
+		fmt.Fprintf(out, "%d 1\n%010d 00000 n \n", nr, offsets[nr])
+	}
+	fmt.Fprintf(out, "trailer\n<</Size %d /Root %s /Info %s /ID %s /Prev %s>>\nstartxref\n%d\n%%%%EOF\n",
+		metadataNr+1, ctx.Root.PDFString(), ctx.Info.PDFString(), ctx.ID.PDFString(), match[1], xrefOffset)
+	return out.Bytes(), nil
+}
+
+// documentInfo reads the document info dictionary as text
+func documentInfo(ctx *model.Context) (map[string]string, error) {
+	d, err := ctx.DereferenceDict(*ctx.Info)
+	if err != nil {
+		return nil, err
+	}
+	info := make(map[string]string)
+	for key, value := range d {
+		obj, err := ctx.Dereference(value)
+		if err != nil {
+			return nil, err
+		}
+		if text, err := types.StringOrHexLiteral(obj); err == nil {
+			info[key] = *text
+		}
+	}
+	return info, nil
+}
+
+// xmpPacket repeats the document info in XMP, as PDF/A requires, and
+// claims PDF/A-2b conformance
+func xmpPacket(info map[string]string) []byte {
+	var props strings.Builder
+	fmt.Fprintf(&props, "   <pdfaid:part>%d</pdfaid:part>\n   <pdfaid:conformance>%s</pdfaid:conformance>\n", pdfaPart, pdfaConformance)
+	props.WriteString("   <dc:format>application/pdf</dc:format>\n")
+	if v := info["Title"]; v != "" {
+		fmt.Fprintf(&props, "   <dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmlEscape(v))
+	}
+	if v := info["Author"]; v != "" {
+		fmt.Fprintf(&props, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(v))
+	}
+	if v := info["Subject"]; v != "" {
+		fmt.Fprintf(&props, "   <dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", xmlEscape(v))
+	}
+	simple := []struct{ key, property string }{
+		{"Keywords", "pdf:Keywords"},
+		{"Producer", "pdf:Producer"},
+		{"Creator", "xmp:CreatorTool"},
+	}
+	for _, entry := range simple {
+		if v := info[entry.key]; v != "" {
+			fmt.Fprintf(&props, "   <%s>%s</%s>\n", entry.property, xmlEscape(v), entry.property)
+		}
+	}
+	dates := []struct{ key, property string }{
+		{"CreationDate", "xmp:CreateDate"},
+		{"ModDate", "xmp:ModifyDate"},
+	}
+	for _, entry := range dates {
+		if t, ok := types.DateTime(info[entry.key], true); ok {
+			fmt.Fprintf(&props, "   <%s>%s</%s>\n", entry.property, t.Format(time.RFC3339), entry.property)
+		}
+	}
+
+	return []byte("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n" +
+		"<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n" +
+		" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n" +
+		"  <rdf:Description rdf:about=\"\"\n" +
+		"    xmlns:pdfaid=\"http://www.aiim.org/pdfa/ns/id/\"\n" +
+		"    xmlns:dc=\"http://purl.org/dc/elements/1.1/\"\n" +
+		"    xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"\n" +
+		"    xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\">\n" +
+		props.String() +
+		"  </rdf:Description>\n" +
+		" </rdf:RDF>\n" +
+		"</x:xmpmeta>\n" +
+		"<?xpacket end=\"r\"?>")
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// checkPDFA validates the PDF with pdfcpu and checks the PDF/A-2b
+// requirements the conversion is responsible for
+func checkPDFA(data []byte) error {
+	ctx, err := readPDF(data)
+	if err != nil {
+		return fmt.Errorf("PDF/A check: %w", err)
+	}
+
+	var failures []string
+	fail := func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+
+	if header, _, _ := bytes.Cut(data, []byte("\n")); !bytes.HasPrefix(header, []byte("%PDF-1.")) {
+		fail("file doesn't start with a PDF header")
+	} else if comment, _, _ := bytes.Cut(data[len(header)+1:], []byte("\n")); !binaryComment(comment) {
+		fail("header isn't followed by a binary comment")
+	}
+	if ctx.Encrypt != nil {
+		fail("file is encrypted")
+	}
+	if len(ctx.ID) != 2 {
+		fail("trailer has no file ID")
+	}
+
+	root, err := ctx.Catalog()
+	if err != nil {
+		return fmt.Errorf("PDF/A check: %w", err)
+	}
+	if _, ok := root.Find("AA"); ok {
+		fail("catalog has additional actions")
+	}
+	if names := root.DictEntry("Names"); names != nil {
+		if _, ok := names.Find("JavaScript"); ok {
+			fail("document contains JavaScript")
+		}
+	}
+	checkMetadata(ctx, root, fail)
+	checkOutputIntent(ctx, root, fail)
+	if err := checkPages(ctx, fail); err != nil {
+		return fmt.Errorf("PDF/A check: %w", err)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("output is not PDF/A-%d%s: %s", pdfaPart, strings.ToLower(pdfaConformance), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// binaryComment reports whether line is a comment of at least four bytes
+// above 127, which marks the file as binary
+func binaryComment(line []byte) bool {
+	if len(line) < 5 || line[0] != '%' {
+		return false
+	}
+	for _, b := range line[1:5] {
+		if b <= 127 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkMetadata checks the XMP packet claims PDF/A and repeats the
+// document info
+func checkMetadata(ctx *model.Context, root types.Dict, fail func(string, ...interface{})) {
+	sd, _, err := ctx.DereferenceStreamDict(root["Metadata"])
+	if err != nil || sd == nil {
+		fail("catalog has no XMP metadata")
+		return
+	}
+	if len(sd.FilterPipeline) > 0 {
+		fail("XMP metadata is compressed")
+	}
+	if err := sd.Decode(); err != nil {
+		fail("XMP metadata can't be read: %v", err)
+		return
+	}
+	packet := string(sd.Content)
+	if !strings.Contains(packet, fmt.Sprintf("<pdfaid:part>%d</pdfaid:part>", pdfaPart)) ||
+		!strings.Contains(packet, fmt.Sprintf("<pdfaid:conformance>%s</pdfaid:conformance>", pdfaConformance)) {
+		fail("XMP metadata doesn't identify the file as PDF/A-%d%s", pdfaPart, strings.ToLower(pdfaConformance))
+	}
+
+	info, err := documentInfo(ctx)
+	if err != nil {
+		fail("document info can't be read: %v", err)
+		return
+	}
+	for _, key := range []string{"Title", "Author", "Subject", "Keywords", "Producer", "Creator"} {
+		if v := info[key]; v != "" && !strings.Contains(packet, ">"+xmlEscape(v)+"<") {
+			fail("XMP metadata doesn't repeat the document %s", key)
+		}
+	}
+	for _, key := range []string{"CreationDate", "ModDate"} {
+		t, ok := types.DateTime(info[key], true)
+		if !ok {
+			continue
+		}
+		if !pdfDateZonePattern.MatchString(info[key]) {
+			fail("document %s has no time zone", key)
+		}
+		if !strings.Contains(packet, ">"+t.Format(time.RFC3339)+"<") {
+			fail("XMP metadata doesn't repeat the document %s", key)
+		}
+	}
+}
+
+// pdfDateZonePattern matches a PDF date that runs to the seconds and ends
+// in a UTC offset
+var pdfDateZonePattern = regexp.MustCompile(`^D:\d{14}(Z|[+-]\d{2}'\d{2}'?)`)
+
+// checkOutputIntent checks for a PDF/A output intent with an ICC profile
+func checkOutputIntent(ctx *model.Context, root types.Dict, fail func(string, ...interface{})) {
+	intents, err := ctx.DereferenceArray(root["OutputIntents"])
+	if err == nil {
+		for _, intent := range intents {
+			d, err := ctx.DereferenceDict(intent)
+			if err != nil || d == nil || d.NameEntry("S") == nil || *d.NameEntry("S") != "GTS_PDFA1" {
+				continue
+			}
+			if profile, _, err := ctx.DereferenceStreamDict(d["DestOutputProfile"]); err == nil && profile != nil {
+				return
+			}
+		}
+	}
+	fail("catalog has no PDF/A output intent with an ICC profile")
+}
+
+// checkPages checks every font is embedded and every annotation printable
+func checkPages(ctx *model.Context, fail func(string, ...interface{})) error {
+	checked := make(map[string]bool)
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		page, _, inherited, err := ctx.PageDict(pageNr, true)
+		if err != nil {
+			return err
+		}
+
+		fonts, err := ctx.DereferenceDict(inherited.Resources["Font"])
+		if err != nil {
+			return err
+		}
+		for name, ref := range fonts {
+			font, err := ctx.DereferenceDict(ref)
+			if err != nil {
+				return err
+			}
+			baseFont := name
+			if v := font.NameEntry("BaseFont"); v != nil {
+				baseFont = *v
+			}
+			if checked[baseFont] {
+				continue
+			}
+			checked[baseFont] = true
+			embedded, err := fontEmbedded(ctx, font)
+			if err != nil {
+				return err
+			}
+			if !embedded {
+				fail("font %s isn't embedded", baseFont)
+			}
+		}
+
+		annots, err := ctx.DereferenceArray(page["Annots"])
+		if err != nil {
+			return err
+		}
+		for _, annot := range annots {
+			d, err := ctx.DereferenceDict(annot)
+			if err != nil {
+				return err
+			}
+			if flags := d.IntEntry("F"); flags == nil || *flags&4 == 0 || *flags&(1|2|32) != 0 {
+				fail("an annotation on page %d isn't printable", pageNr)
+			}
+		}
+	}
+	return nil
+}
+
+// fontEmbedded reports whether a font carries its font program. Type 3
+// fonts are drawn by the content itself.
+func fontEmbedded(ctx *model.Context, font types.Dict) (bool, error) {
+	subtype := font.Subtype()
+	if subtype != nil && *subtype == "Type3" {
+		return true, nil
+	}
+	if subtype != nil && *subtype == "Type0" {
+		descendants, err := ctx.DereferenceArray(font["DescendantFonts"])
+		if err != nil || len(descendants) == 0 {
+			return false, err
+		}
+		if font, err = ctx.DereferenceDict(descendants[0]); err != nil {
+			return false, err
+		}
+	}
+
+	descriptor, err := ctx.DereferenceDict(font["FontDescriptor"])
+	if err != nil || descriptor == nil {
+		return false, err
+	}
+	for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		if _, ok := descriptor.Find(key); ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// srgbProfile builds a version 2 ICC display profile for sRGB, for the
+// output intent: the sRGB primaries adapted to D50 and the sRGB tone curve
+// sampled at 1024 points. The header is dated created, in UTC.
+func srgbProfile(created time.Time) []byte {
+	text := func(sig, value string) []byte {
+		var b bytes.Buffer
+		b.WriteString(sig)
+		b.Write(make([]byte, 4))
+		if sig == "desc" {
+			binary.Write(&b, binary.BigEndian, uint32(len(value)+1))
+			b.WriteString(value)
+			b.WriteByte(0)
+			b.Write(make([]byte, 4+4+2+1+67)) // no Unicode or ScriptCode description
+		} else {
+			b.WriteString(value)
+			b.WriteByte(0)
+		}
+		return b.Bytes()
+	}
+	xyz := func(x, y, z float64) []byte {
+		var b bytes.Buffer
+		b.WriteString("XYZ ")
+		b.Write(make([]byte, 4))
+		for _, v := range []float64{x, y, z} {
+			binary.Write(&b, binary.BigEndian, int32(math.Round(v*65536)))
+		}
+		return b.Bytes()
+	}
+
+	var curve bytes.Buffer
+	curve.WriteString("curv")
+	curve.Write(make([]byte, 4))
+	binary.Write(&curve, binary.BigEndian, uint32(1024))
+	for i := 0; i < 1024; i++ {
+		v := float64(i) / 1023
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		binary.Write(&curve, binary.BigEndian, uint16(math.Round(v*65535)))
+	}
+
+	tags := []struct {
+		sig  string
+		data []byte
+	}{
+		{"desc", text("desc", srgbIdentifier)},
+		{"cprt", text("text", "No copyright, use freely")},
+		{"wtpt", xyz(0.9505, 1.0, 1.0890)},
+		{"rXYZ", xyz(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", xyz(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", xyz(0.1431, 0.0606, 0.7141)},
+		{"rTRC", curve.Bytes()},
+		{"gTRC", nil}, // the green and blue curves share the red one
+		{"bTRC", nil},
+	}
+
+	// Tag data starts after the 128 byte header and the tag table, each
+	// element aligned to four bytes
+	var table, data bytes.Buffer
+	offset := 128 + 4 + 12*len(tags)
+	var curveOffset, curveSize int
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	for _, tag := range tags {
+		start, size := offset+data.Len(), len(tag.data)
+		if tag.data == nil {
+			start, size = curveOffset, curveSize
+		} else {
+			data.Write(tag.data)
+			for data.Len()%4 != 0 {
+				data.WriteByte(0)
+			}
+		}
+		if tag.sig == "rTRC" {
+			curveOffset, curveSize = start, size
+		}
+		table.WriteString(tag.sig)
+		binary.Write(&table, binary.BigEndian, uint32(start))
+		binary.Write(&table, binary.BigEndian, uint32(size))
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(offset+data.Len()))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // version 2.1
+	copy(header[12:], "mntrRGB XYZ ")
+	created = created.UTC()
+	date := []int{created.Year(), int(created.Month()), created.Day(), created.Hour(), created.Minute(), created.Second()}
+	for i, v := range date {
+		binary.BigEndian.PutUint16(header[24+2*i:], uint16(v))
+	}
+	copy(header[36:], "acsp")
+	for i, v := range []float64{0.9642, 1.0, 0.8249} { // D50, the PCS illuminant
+		binary.BigEndian.PutUint32(header[68+4*i:], uint32(int32(math.Round(v*65536))))
+	}
+
+	return append(append(header, table.Bytes()...), data.Bytes()...)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	"resume-builder/utils"
+)
+
+// TestPDFAOutput renders the example resume as PDF/A and checks that pdfcpu
+// reads the hand-appended update and validates the result strictly
+func TestPDFAOutput(t *testing.T) {
+	cfg, content, err := utils.LoadInputs("config.json", "example-resume.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.PDF.PDFA = true
+	cfg.PDF.CreationDate = "2024-03-01T09:30:00+02:00"
+
+	data, err := renderPDF(cfg, content, "template-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := pdfcpuConfig()
+	conf.ValidationMode = model.ValidationStrict
+	ctx, err := api.ReadContext(bytes.NewReader(data), conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.ValidateContext(ctx); err != nil {
+		t.Fatalf("pdfcpu validation: %v", err)
+	}
+
+	info, err := documentInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := info["CreationDate"], "D:20240301073000+00'00'"; got != want {
+		t.Errorf("CreationDate = %q, want %q", got, want)
+	}
+	if !pdfDateZonePattern.MatchString(info["ModDate"]) {
+		t.Errorf("ModDate %q has no time zone", info["ModDate"])
+	}
+
+	root, err := ctx.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd, _, err := ctx.DereferenceStreamDict(root["Metadata"])
+	if err != nil || sd == nil {
+		t.Fatalf("no XMP metadata: %v", err)
+	}
+	if err := sd.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<xmp:CreateDate>2024-03-01T07:30:00Z</xmp:CreateDate>"; !strings.Contains(string(sd.Content), want) {
+		t.Errorf("XMP metadata lacks %s", want)
+	}
+}
+
+func TestSRGBProfileDate(t *testing.T) {
+	created, err := utils.ParseCreationDate("2024-03-01T09:30:15+02:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := srgbProfile(created)[24:36]
+	want := []uint16{2024, 3, 1, 7, 30, 15}
+	for i, v := range want {
+		if got := binary.BigEndian.Uint16(header[2*i:]); got != v {
+			t.Errorf("profile date field %d = %d, want %d", i, got, v)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 func BuildTemplate1(mrt core.Maroto, cfg *utils.Config, content *utils.Content) error {
 	// Header Section
 	if cfg.Sections["header"].Enabled {
+		beginSection(mrt, "header")
 		err := buildHeaderSection(mrt, cfg, content)
 		if err != nil {
 			return err
@@ -24,6 +25,7 @@ func BuildTemplate1(mrt core.Maroto, cfg *utils.Config, content *utils.Content)
 
 	// Contact Section
 	if cfg.Sections["contact"].Enabled {
+		beginSection(mrt, "contact")
 		err := buildContactSection(mrt, cfg, content)
 		if err != nil {
 			return err
@@ -37,9 +39,7 @@ func BuildTemplate1(mrt core.Maroto, cfg *utils.Config, content *utils.Content)
 	}
 
 	// Dynamic sections in order
-	sectionOrder := []string{"summary", "experience", "education", "skills", "certifications"}
-	
-	for _, sectionKey := range sectionOrder {
+	for _, sectionKey := range utils.SectionOrder {
 		sectionCfg, exists := cfg.Sections[sectionKey]
 		if !exists || !sectionCfg.Enabled {
 			continue
@@ -50,57 +50,21 @@ func BuildTemplate1(mrt core.Maroto, cfg *utils.Config, content *utils.Content)
 			continue
 		}
 
+		beginSection(mrt, sectionKey)
+
 		switch sectionCfg.Template {
 		case "simple_list":
 			err := buildSimpleListSection(mrt, cfg, sectionCfg, sectionData)
 			if err != nil {
 				return err
 			}
-		case "entry_list":
-			// Convert to SectionData struct for entry lists
-			var data utils.SectionData
-			if dataMap, ok := sectionData.(map[string]interface{}); ok {
-				if items, exists := dataMap["items"]; exists {
-					// Convert interface{} to []EntryItem
-					if itemsSlice, ok := items.([]interface{}); ok {
-						for _, item := range itemsSlice {
-							if itemMap, ok := item.(map[string]interface{}); ok {
-								var entry utils.EntryItem
-								// Convert each field
-								if title, ok := itemMap["title"].(string); ok {
-									entry.Title = &title
-								}
-								if company, ok := itemMap["company"].(string); ok {
-									entry.Company = &company
-								}
-								if institution, ok := itemMap["institution"].(string); ok {
-									entry.Institution = &institution
-								}
-								if degree, ok := itemMap["degree"].(string); ok {
-									entry.Degree = &degree
-								}
-								if location, ok := itemMap["location"].(string); ok {
-									entry.Location = &location
-								}
-								if startDate, ok := itemMap["start_date"].(string); ok {
-									entry.StartDate = &startDate
-								}
-								if endDate, ok := itemMap["end_date"].(string); ok {
-									entry.EndDate = &endDate
-								}
-								if desc, ok := itemMap["description"].([]interface{}); ok {
-									for _, d := range desc {
-										if descStr, ok := d.(string); ok {
-											entry.Description = append(entry.Description, descStr)
-										}
-									}
-								}
-								data.Items = append(data.Items, entry)
-							}
-						}
-					}
-				}
+		case "publications":
+			err := buildPublicationsSection(mrt, cfg, sectionCfg, sectionData, content)
+			if err != nil {
+				return err
 			}
+		case "entry_list":
+			data := entryListData(sectionData)
 			err := buildEntryListSection(mrt, cfg, sectionCfg, data)
 			if err != nil {
 				return err
@@ -111,6 +75,54 @@ func BuildTemplate1(mrt core.Maroto, cfg *utils.Config, content *utils.Content)
 	return nil
 }
 
+// entryListData converts the decoded items of an entry list section
+func entryListData(sectionData interface{}) utils.SectionData {
+	var data utils.SectionData
+	if dataMap, ok := sectionData.(map[string]interface{}); ok {
+		if items, exists := dataMap["items"]; exists {
+			// Convert interface{} to []EntryItem
+			if itemsSlice, ok := items.([]interface{}); ok {
+				for _, item := range itemsSlice {
+					if itemMap, ok := item.(map[string]interface{}); ok {
+						var entry utils.EntryItem
+						// Convert each field
+						if title, ok := itemMap["title"].(string); ok {
+							entry.Title = &title
+						}
+						if company, ok := itemMap["company"].(string); ok {
+							entry.Company = &company
+						}
+						if institution, ok := itemMap["institution"].(string); ok {
+							entry.Institution = &institution
+						}
+						if degree, ok := itemMap["degree"].(string); ok {
+							entry.Degree = &degree
+						}
+						if location, ok := itemMap["location"].(string); ok {
+							entry.Location = &location
+						}
+						if startDate, ok := itemMap["start_date"].(string); ok {
+							entry.StartDate = &startDate
+						}
+						if endDate, ok := itemMap["end_date"].(string); ok {
+							entry.EndDate = &endDate
+						}
+						if desc, ok := itemMap["description"].([]interface{}); ok {
+							for _, d := range desc {
+								if descStr, ok := d.(string); ok {
+									entry.Description = append(entry.Description, descStr)
+								}
+							}
+						}
+						data.Items = append(data.Items, entry)
+					}
+				}
+			}
+		}
+	}
+	return data
+}
+
 func buildHeaderSection(mrt core.Maroto, cfg *utils.Config, content *utils.Content) error {
 	headerFont := cfg.Fonts["header"]
 	headerColor := utils.ResolveColor(headerFont.Color, cfg.Colors)
@@ -177,45 +189,44 @@ func buildSimpleListSection(mrt core.Maroto, cfg *utils.Config, sectionCfg utils
 	bodyColor := utils.ResolveColor(bodyFont.Color, cfg.Colors)
 	bodySpacing := cfg.Spacing[template.Spacing]
 
-	// Handle different data types
-	switch data := sectionData.(type) {
-	case map[string]interface{}:
-		// Handle object with content field (summary)
-		if content, ok := data["content"].(string); ok {
-			mrt.AddRow(bodySpacing,
-				text.NewCol(12, content, props.Text{
-					Family: bodyFont.Family,
-					Size:   bodyFont.Size,
-					Style:  utils.ResolveFontStyle(bodyFont.Style),
-					Color:  &bodyColor,
-					Align:  align.Left,
-				}),
-			)
-		}
-	case []interface{}:
-		// Handle array of strings (skills, certifications)
-		var items []string
+	if bodyText, ok := simpleListText(sectionData); ok {
+		mrt.AddRow(bodySpacing,
+			text.NewCol(12, bodyText, props.Text{
+				Family: bodyFont.Family,
+				Size:   bodyFont.Size,
+				Style:  utils.ResolveFontStyle(bodyFont.Style),
+				Color:  &bodyColor,
+				Align:  align.Left,
+			}),
+		)
+	}
+
+	return nil
+}
+
+// simpleListText is the body of a simple list section: the content of an
+// object (summary) or the items of an array joined on one line (skills,
+// certifications)
+func simpleListText(sectionData interface{}) (string, bool) {
+	if data, ok := sectionData.(map[string]interface{}); ok {
+		content, ok := data["content"].(string)
+		return content, ok
+	}
+	items := simpleListItems(sectionData)
+	return strings.Join(items, " | "), len(items) > 0
+}
+
+// simpleListItems are the strings of an array section
+func simpleListItems(sectionData interface{}) []string {
+	var items []string
+	if data, ok := sectionData.([]interface{}); ok {
 		for _, item := range data {
 			if str, ok := item.(string); ok {
 				items = append(items, str)
 			}
 		}
-		
-		if len(items) > 0 {
-			itemsText := strings.Join(items, " | ")
-			mrt.AddRow(bodySpacing,
-				text.NewCol(12, itemsText, props.Text{
-					Family: bodyFont.Family,
-					Size:   bodyFont.Size,
-					Style:  utils.ResolveFontStyle(bodyFont.Style),
-					Color:  &bodyColor,
-					Align:  align.Left,
-				}),
-			)
-		}
 	}
-
-	return nil
+	return items
 }
 
 func buildEntryListSection(mrt core.Maroto, cfg *utils.Config, sectionCfg utils.SectionConfig, data utils.SectionData) error {
@@ -239,13 +250,7 @@ func buildEntryListSection(mrt core.Maroto, cfg *utils.Config, sectionCfg utils.
 
 	for _, item := range data.Items {
 		// Job/Education title and company/institution
-		var titleLine string
-		if item.Title != nil && item.Company != nil {
-			titleLine = *item.Title + " - " + *item.Company
-		} else if item.Degree != nil && item.Institution != nil {
-			titleLine = *item.Degree + " - " + *item.Institution
-		}
-
+		titleLine := entryHeading(item)
 		if titleLine != "" {
 			mrt.AddRow(itemSpacing,
 				text.NewCol(12, titleLine, props.Text{
@@ -259,25 +264,7 @@ func buildEntryListSection(mrt core.Maroto, cfg *utils.Config, sectionCfg utils.
 		}
 
 		// Location and dates
-		var locationLine string
-		parts := []string{}
-		if item.Location != nil {
-			parts = append(parts, *item.Location)
-		}
-		if item.StartDate != nil || item.EndDate != nil {
-			dateStr := ""
-			if item.StartDate != nil {
-				dateStr += *item.StartDate
-			}
-			dateStr += " - "
-			if item.EndDate != nil {
-				dateStr += *item.EndDate
-			}
-			parts = append(parts, dateStr)
-		}
-		
-		if len(parts) > 0 {
-			locationLine = strings.Join(parts, " | ")
+		if locationLine := entryLocationLine(item); locationLine != "" {
 			mrt.AddRow(itemSpacing-2,
 				text.NewCol(12, locationLine, props.Text{
 					Family: bodyFont.Family,
@@ -306,4 +293,35 @@ func buildEntryListSection(mrt core.Maroto, cfg *utils.Config, sectionCfg utils.
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// entryHeading is the title line of an entry: the job title and company,
+// or the degree and institution. Entries missing either half have none.
+func entryHeading(item utils.EntryItem) string {
+	if item.Title != nil && item.Company != nil {
+		return *item.Title + " - " + *item.Company
+	} else if item.Degree != nil && item.Institution != nil {
+		return *item.Degree + " - " + *item.Institution
+	}
+	return ""
+}
+
+// entryLocationLine joins the location and date range of an entry
+func entryLocationLine(item utils.EntryItem) string {
+	parts := []string{}
+	if item.Location != nil {
+		parts = append(parts, *item.Location)
+	}
+	if item.StartDate != nil || item.EndDate != nil {
+		dateStr := ""
+		if item.StartDate != nil {
+			dateStr += *item.StartDate
+		}
+		dateStr += " - "
+		if item.EndDate != nil {
+			dateStr += *item.EndDate
+		}
+		parts = append(parts, dateStr)
+	}
+	return strings.Join(parts, " | ")
+}
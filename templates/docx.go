@@ -0,0 +1,354 @@
+package templates
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+
+	"resume-builder/utils"
+)
+
+// BuildDOCX renders the resume as a Word document, following the section
+// order of BuildTemplate1. The configured fonts and colors become Word
+// styles (section titles are Heading 1, entry titles Heading 2) so the
+// document stays editable; bullets are a Word list and contact links are
+// hyperlinks.
+func BuildDOCX(cfg *utils.Config, content *utils.Content) ([]byte, error) {
+	doc := &docxDocument{}
+
+	if cfg.Sections["header"].Enabled {
+		doc.paragraph("Title", docxRun{Text: content.Personal.Name})
+	}
+
+	if cfg.Sections["contact"].Enabled {
+		writeDOCXContacts(doc, content)
+	}
+
+	for _, sectionKey := range utils.SectionOrder {
+		sectionCfg, exists := cfg.Sections[sectionKey]
+		if !exists || !sectionCfg.Enabled {
+			continue
+		}
+
+		sectionData, hasData := content.Sections[sectionKey]
+		if !hasData {
+			continue
+		}
+
+		doc.paragraph("Heading1", docxRun{Text: sectionCfg.Title})
+
+		switch sectionCfg.Template {
+		case "simple_list":
+			if bodyText, ok := simpleListText(sectionData); ok {
+				doc.paragraph("Normal", docxRun{Text: bodyText})
+			}
+		case "publications":
+			citations, err := utils.LoadCitations(content, sectionData, sectionCfg.Style)
+			if err != nil {
+				return nil, err
+			}
+			for _, citation := range citations {
+				var runs []docxRun
+				for _, run := range citation.Runs {
+					runs = append(runs, docxRun(run))
+				}
+				doc.paragraph("Citation", runs...)
+			}
+		case "entry_list":
+			for _, item := range entryListData(sectionData).Items {
+				if heading := entryHeading(item); heading != "" {
+					doc.paragraph("Heading2", docxRun{Text: heading})
+				}
+				if location := entryLocationLine(item); location != "" {
+					doc.paragraph("EntryDetails", docxRun{Text: location})
+				}
+				for _, bullet := range item.Description {
+					doc.paragraph("ListBullet", docxRun{Text: bullet})
+				}
+			}
+		}
+	}
+
+	return doc.archive(cfg, content)
+}
+
+// writeDOCXContacts writes the contact fields three to a line, like the
+// rows of the PDF, in one paragraph underlined as the divider
+func writeDOCXContacts(doc *docxDocument, content *utils.Content) {
+	var runs []docxRun
+	count := 0
+	for _, field := range content.ContactFields {
+		text := utils.ResolveTemplate(field.Content, content)
+		if text == "" {
+			continue
+		}
+		if count > 0 && count%3 == 0 {
+			runs = append(runs, docxRun{Text: "\n"})
+		} else if count > 0 {
+			runs = append(runs, docxRun{Text: "  |  "})
+		}
+		run := docxRun{Text: text}
+		if field.Type != nil && *field.Type == "link" && field.Link != nil {
+			run.Link = utils.ResolveTemplate(*field.Link, content)
+		}
+		runs = append(runs, run)
+		count++
+	}
+	if len(runs) > 0 {
+		doc.paragraph("Contact", runs...)
+	}
+}
+
+// docxRun is a run of text; it has the fields of utils.TextRun so
+// citations convert directly
+type docxRun struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Link   string
+}
+
+// docxDocument collects the body of word/document.xml and the hyperlink
+// targets it refers to
+type docxDocument struct {
+	body  bytes.Buffer
+	links []string
+}
+
+// Relationship IDs of the document part: the styles and numbering parts
+// come first, hyperlinks after them
+const (
+	docxStylesRel    = "rId1"
+	docxNumberingRel = "rId2"
+	docxFirstLinkRel = 3
+)
+
+func (d *docxDocument) paragraph(style string, runs ...docxRun) {
+	fmt.Fprintf(&d.body, `<w:p><w:pPr><w:pStyle w:val="%s"/></w:pPr>`, style)
+	for _, run := range runs {
+		if run.Link == "" {
+			d.run(run, "")
+			continue
+		}
+		d.links = append(d.links, run.Link)
+		fmt.Fprintf(&d.body, `<w:hyperlink r:id="rId%d" w:history="1">`, docxFirstLinkRel+len(d.links)-1)
+		d.run(run, "Hyperlink")
+		d.body.WriteString(`</w:hyperlink>`)
+	}
+	d.body.WriteString(`</w:p>`)
+}
+
+// run writes text with its formatting; line breaks in text become breaks
+func (d *docxDocument) run(run docxRun, charStyle string) {
+	d.body.WriteString(`<w:r>`)
+	if charStyle != "" || run.Bold || run.Italic {
+		d.body.WriteString(`<w:rPr>`)
+		if charStyle != "" {
+			fmt.Fprintf(&d.body, `<w:rStyle w:val="%s"/>`, charStyle)
+		}
+		if run.Bold {
+			d.body.WriteString(`<w:b/>`)
+		}
+		if run.Italic {
+			d.body.WriteString(`<w:i/>`)
+		}
+		d.body.WriteString(`</w:rPr>`)
+	}
+	for i, line := range strings.Split(run.Text, "\n") {
+		if i > 0 {
+			d.body.WriteString(`<w:br/>`)
+		}
+		if line != "" {
+			fmt.Fprintf(&d.body, `<w:t xml:space="preserve">%s</w:t>`, xmlEscape(line))
+		}
+	}
+	d.body.WriteString(`</w:r>`)
+}
+
+// archive packages the document with its styles, numbering and
+// relationships as a .docx file
+func (d *docxDocument) archive(cfg *utils.Config, content *utils.Content) ([]byte, error) {
+	margins := cfg.PDF.Margins
+	document := docxHeader + `<w:document xmlns:w="` + docxMainNS + `" xmlns:r="` + docxRelNS + `"><w:body>` +
+		d.body.String() +
+		fmt.Sprintf(`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>`,
+			twips(margins.Top), twips(margins.Right), twips(margins.Bottom), twips(margins.Left)) +
+		`</w:body></w:document>`
+
+	var rels strings.Builder
+	rels.WriteString(docxHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	fmt.Fprintf(&rels, `<Relationship Id="%s" Type="%s/styles" Target="styles.xml"/>`, docxStylesRel, docxRelNS)
+	fmt.Fprintf(&rels, `<Relationship Id="%s" Type="%s/numbering" Target="numbering.xml"/>`, docxNumberingRel, docxRelNS)
+	for i, link := range d.links {
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="%s/hyperlink" Target="%s" TargetMode="External"/>`, docxFirstLinkRel+i, docxRelNS, xmlEscape(link))
+	}
+	rels.WriteString(`</Relationships>`)
+
+	name := xmlEscape(content.Personal.Name)
+	parts := []struct{ name, data string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxPackageRels},
+		{"docProps/core.xml", docxHeader + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>` + name + `</dc:title><dc:creator>` + name + `</dc:creator></cp:coreProperties>`},
+		{"word/document.xml", document},
+		{"word/_rels/document.xml.rels", rels.String()},
+		{"word/styles.xml", docxStyles(cfg)},
+		{"word/numbering.xml", docxNumbering},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, part := range parts {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.data)); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// docxStyle is a Word style built from a configured font
+type docxStyle struct {
+	id, name  string
+	kind      string // paragraph or character
+	font      string // key in cfg.Fonts
+	sizeDelta float64
+	italic    bool
+	paragraph string // extra paragraph properties, in schema order
+}
+
+// docxStyles maps the configured fonts and colors to the styles the
+// document uses. Word's built-in names keep headings in the navigation
+// pane and the outline.
+func docxStyles(cfg *utils.Config) string {
+	contactFont := cfg.SectionTemplates["contact"].Font
+	divider := hexValue(cfg.Colors["secondary"])
+	styles := []docxStyle{
+		{id: "Normal", name: "Normal", kind: "paragraph", font: "body", paragraph: `<w:spacing w:after="60"/>`},
+		{id: "Title", name: "Title", kind: "paragraph", font: "header", paragraph: `<w:spacing w:after="120"/>`},
+		{id: "Contact", name: "Contact", kind: "paragraph", font: contactFont,
+			paragraph: `<w:pBdr><w:bottom w:val="single" w:sz="8" w:space="6" w:color="` + divider + `"/></w:pBdr><w:spacing w:after="120"/>`},
+		{id: "Heading1", name: "heading 1", kind: "paragraph", font: "section_title", paragraph: `<w:keepNext/><w:spacing w:before="240" w:after="80"/><w:outlineLvl w:val="0"/>`},
+		{id: "Heading2", name: "heading 2", kind: "paragraph", font: "emphasis", paragraph: `<w:keepNext/><w:spacing w:before="120" w:after="0"/><w:outlineLvl w:val="1"/>`},
+		{id: "EntryDetails", name: "Entry Details", kind: "paragraph", font: "body", sizeDelta: -1, italic: true, paragraph: `<w:keepNext/><w:spacing w:after="40"/>`},
+		{id: "ListBullet", name: "List Bullet", kind: "paragraph", font: "body", sizeDelta: -1,
+			paragraph: `<w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr><w:spacing w:after="0"/>`},
+		{id: "Citation", name: "Citation", kind: "paragraph", font: "body", sizeDelta: -1,
+			paragraph: `<w:spacing w:before="80" w:after="0"/><w:ind w:left="283" w:hanging="283"/>`},
+	}
+
+	var out strings.Builder
+	out.WriteString(docxHeader + `<w:styles xmlns:w="` + docxMainNS + `">`)
+	for _, style := range styles {
+		fmt.Fprintf(&out, `<w:style w:type="%s" w:styleId="%s"`, style.kind, style.id)
+		if style.id == "Normal" {
+			out.WriteString(` w:default="1"`)
+		}
+		fmt.Fprintf(&out, `><w:name w:val="%s"/>`, style.name)
+		if style.id != "Normal" {
+			out.WriteString(`<w:basedOn w:val="Normal"/><w:next w:val="Normal"/>`)
+		}
+		out.WriteString(`<w:qFormat/>`)
+		if style.paragraph != "" {
+			out.WriteString(`<w:pPr>` + style.paragraph + `</w:pPr>`)
+		}
+		out.WriteString(docxFontProps(cfg, cfg.Fonts[style.font], style.sizeDelta, style.italic))
+		out.WriteString(`</w:style>`)
+	}
+
+	link := hexValue(utils.ResolveIconColor(&utils.IconConfig{Color: "link"}, cfg.Colors))
+	fmt.Fprintf(&out, `<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:rPr><w:color w:val="%s"/><w:u w:val="single"/></w:rPr></w:style>`, link)
+	out.WriteString(`</w:styles>`)
+	return out.String()
+}
+
+// docxFontProps writes run properties in the order the schema requires
+func docxFontProps(cfg *utils.Config, font utils.FontDefinition, sizeDelta float64, italic bool) string {
+	var props strings.Builder
+	props.WriteString(`<w:rPr>`)
+	if font.Family != "" {
+		family := xmlEscape(docxFamily(font.Family))
+		fmt.Fprintf(&props, `<w:rFonts w:ascii="%s" w:hAnsi="%s" w:cs="%s"/>`, family, family, family)
+	}
+	style := strings.ToLower(font.Style)
+	if style == "bold" || style == "bolditalic" {
+		props.WriteString(`<w:b/>`)
+	}
+	if italic || style == "italic" || style == "bolditalic" {
+		props.WriteString(`<w:i/>`)
+	}
+	color := utils.ResolveColor(font.Color, cfg.Colors)
+	fmt.Fprintf(&props, `<w:color w:val="%02X%02X%02X"/>`, color.Red, color.Green, color.Blue)
+	if font.Size > 0 {
+		fmt.Fprintf(&props, `<w:sz w:val="%d"/>`, int(math.Round((font.Size+sizeDelta)*2)))
+	}
+	props.WriteString(`</w:rPr>`)
+	return props.String()
+}
+
+// docxFamily names the font Word should use. The PDF core fonts aren't
+// installed with Word, so they become their usual stand-ins.
+func docxFamily(family string) string {
+	switch strings.ToLower(family) {
+	case "helvetica", "arial":
+		return "Arial"
+	case "times":
+		return "Times New Roman"
+	case "courier":
+		return "Courier New"
+	}
+	return family
+}
+
+// hexValue is a #RRGGBB color as Word writes it, black when it isn't one
+func hexValue(color string) string {
+	c := utils.HexToColor(color)
+	return fmt.Sprintf("%02X%02X%02X", c.Red, c.Green, c.Blue)
+}
+
+// twips converts millimetres to twentieths of a point
+func twips(mm float64) int {
+	return int(math.Round(mm * 1440 / 25.4))
+}
+
+func xmlEscape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+const (
+	docxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+	docxMainNS = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	docxRelNS  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+	docxContentTypes = docxHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+		`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+		`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+		`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+		`</Types>`
+
+	docxPackageRels = docxHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="` + docxRelNS + `/officeDocument" Target="word/document.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+		`</Relationships>`
+
+	// docxNumbering defines the bullet list used by the List Bullet style
+	docxNumbering = docxHeader + `<w:numbering xmlns:w="` + docxMainNS + `">` +
+		`<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="singleLevel"/>` +
+		`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="•"/><w:lvlJc w:val="left"/>` +
+		`<w:pPr><w:ind w:left="567" w:hanging="283"/></w:pPr></w:lvl></w:abstractNum>` +
+		`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+		`</w:numbering>`
+)
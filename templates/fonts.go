@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"strings"
+
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontfamily"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core/entity"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"resume-builder/utils"
+)
+
+// The Go fonts are TrueType fonts that ship with the binary. They set the
+// text of the PNG preview, and of PDF/A output, which has to embed every
+// font and so can't use the PDF core fonts.
+const (
+	goFamily     = "Go"
+	goMonoFamily = "Go Mono"
+)
+
+// goFontKey picks one of the Go fonts: Go Mono stands in for Courier, Go
+// for every other family
+type goFontKey struct {
+	mono  bool
+	style fontstyle.Type
+}
+
+var goFontData = map[goFontKey][]byte{
+	{false, fontstyle.Normal}:     goregular.TTF,
+	{false, fontstyle.Bold}:       gobold.TTF,
+	{false, fontstyle.Italic}:     goitalic.TTF,
+	{false, fontstyle.BoldItalic}: gobolditalic.TTF,
+	{true, fontstyle.Normal}:      gomono.TTF,
+	{true, fontstyle.Bold}:        gomonobold.TTF,
+	{true, fontstyle.Italic}:      gomonoitalic.TTF,
+	{true, fontstyle.BoldItalic}:  gomonobolditalic.TTF,
+}
+
+// goFontFor returns the Go font standing in for a family and style
+func goFontFor(family string, style fontstyle.Type) goFontKey {
+	key := goFontKey{mono: EmbeddedFamily(family) == goMonoFamily, style: fontstyle.Normal}
+	switch style {
+	case fontstyle.Bold, fontstyle.Italic, fontstyle.BoldItalic:
+		key.style = style
+	}
+	return key
+}
+
+// isGoFamily reports whether family names one of the Go fonts
+func isGoFamily(family string) bool {
+	return strings.EqualFold(family, goFamily) || strings.EqualFold(family, goMonoFamily)
+}
+
+// EmbeddedFamily names the Go font that stands in for family when fonts
+// have to be embedded
+func EmbeddedFamily(family string) string {
+	if strings.EqualFold(family, fontfamily.Courier) || strings.EqualFold(family, goMonoFamily) {
+		return goMonoFamily
+	}
+	return goFamily
+}
+
+// EmbeddedFonts lists the Go fonts in every style, for maroto to embed
+func EmbeddedFonts() []*entity.CustomFont {
+	var fonts []*entity.CustomFont
+	for _, mono := range []bool{false, true} {
+		family := goFamily
+		if mono {
+			family = goMonoFamily
+		}
+		for _, style := range []fontstyle.Type{fontstyle.Normal, fontstyle.Bold, fontstyle.Italic, fontstyle.BoldItalic} {
+			fonts = append(fonts, &entity.CustomFont{Family: family, Style: style, Bytes: goFontData[goFontKey{mono, style}]})
+		}
+	}
+	return fonts
+}
+
+// WithEmbeddedFonts returns a copy of cfg whose fonts are set in the Go
+// fonts EmbeddedFonts provides
+func WithEmbeddedFonts(cfg *utils.Config) *utils.Config {
+	embedded := *cfg
+	embedded.Fonts = make(map[string]utils.FontDefinition, len(cfg.Fonts))
+	for name, font := range cfg.Fonts {
+		font.Family = EmbeddedFamily(font.Family)
+		embedded.Fonts[name] = font
+	}
+	return &embedded
+}
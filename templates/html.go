@@ -0,0 +1,359 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+
+	"resume-builder/utils"
+)
+
+// htmlPage is the template-1 layout as the HTML template renders it. Fonts
+// and spacing are referenced by CSS class, defined once in Style.
+type htmlPage struct {
+	Title    string
+	Style    template.CSS
+	Header   *htmlText
+	Contact  *htmlContact
+	Sections []htmlSection
+}
+
+type htmlText struct {
+	Text  string
+	Class string
+}
+
+type htmlContact struct {
+	Class        string
+	Fields       []htmlContactField
+	DividerClass string
+}
+
+type htmlContactField struct {
+	Text string
+	Link string
+	Icon template.HTML
+}
+
+type htmlSection struct {
+	Key       string
+	Title     htmlText
+	Icon      template.HTML
+	Body      *htmlText
+	BodyClass string
+	Entries   []htmlEntry
+	Citations []htmlCitation
+}
+
+type htmlEntry struct {
+	Heading     *htmlText
+	Location    *htmlText
+	Bullets     []string
+	BulletClass string
+}
+
+type htmlCitation struct {
+	Runs  []utils.TextRun
+	Class string
+}
+
+// BuildHTML renders a template-1 resume as a standalone HTML page. It walks
+// the same sections as BuildTemplate1; fonts, colors and spacing become CSS,
+// and icons are inlined so the page is a single file.
+func BuildHTML(cfg *utils.Config, content *utils.Content) ([]byte, error) {
+	page := htmlPage{Title: content.Personal.Name, Style: htmlStyle(cfg)}
+	if page.Title == "" {
+		page.Title = "Resume"
+	}
+
+	if cfg.Sections["header"].Enabled {
+		page.Header = &htmlText{
+			Text:  content.Personal.Name,
+			Class: fontClass("header") + " " + spaceClass(cfg.SectionTemplates["header"].Spacing, false),
+		}
+	}
+
+	if cfg.Sections["contact"].Enabled {
+		contactTemplate := cfg.SectionTemplates["contact"]
+		contact := &htmlContact{
+			Class:        fontClass(contactTemplate.Font),
+			DividerClass: spaceClass("medium", false),
+		}
+		for _, field := range content.ContactFields {
+			contact.Fields = append(contact.Fields, htmlContactFieldFor(field, cfg, content))
+		}
+		page.Contact = contact
+	}
+
+	for _, sectionKey := range utils.SectionOrder {
+		sectionCfg, exists := cfg.Sections[sectionKey]
+		if !exists || !sectionCfg.Enabled {
+			continue
+		}
+
+		sectionData, hasData := content.Sections[sectionKey]
+		if !hasData {
+			continue
+		}
+
+		template := cfg.SectionTemplates[sectionCfg.Template]
+		section := htmlSection{
+			Key:   sectionKey,
+			Title: htmlText{Text: sectionCfg.Title, Class: fontClass("section_title") + " " + spaceClass(template.TitleSpacing, false)},
+		}
+		if sectionCfg.Icon != nil && *sectionCfg.Icon != "" {
+			section.Icon = htmlIcon(*sectionCfg.Icon, cfg)
+		}
+
+		bodyClass := fontClass(template.Font)
+		switch sectionCfg.Template {
+		case "simple_list":
+			if bodyText, ok := simpleListText(sectionData); ok {
+				section.Body = &htmlText{Text: bodyText, Class: bodyClass + " " + spaceClass(template.Spacing, false)}
+			}
+		case "publications":
+			citations, err := utils.LoadCitations(content, sectionData, sectionCfg.Style)
+			if err != nil {
+				return nil, err
+			}
+			for _, citation := range citations {
+				section.Citations = append(section.Citations, htmlCitation{
+					Runs:  citation.Runs,
+					Class: bodyClass + " minor " + spaceClass(template.ItemSpacing, false),
+				})
+			}
+		case "entry_list":
+			for _, item := range entryListData(sectionData).Items {
+				entry := htmlEntry{
+					Bullets:     item.Description,
+					BulletClass: spaceClass(template.ItemSpacing, true),
+				}
+				if heading := entryHeading(item); heading != "" {
+					entry.Heading = &htmlText{Text: heading, Class: fontClass("emphasis") + " " + spaceClass(template.ItemSpacing, false)}
+				}
+				if location := entryLocationLine(item); location != "" {
+					entry.Location = &htmlText{Text: location, Class: bodyClass + " minor " + spaceClass(template.ItemSpacing, true)}
+				}
+				section.Entries = append(section.Entries, entry)
+			}
+			section.BodyClass = bodyClass + " minor"
+		default:
+			continue
+		}
+
+		page.Sections = append(page.Sections, section)
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, page); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// htmlContactFieldFor resolves a contact field the way CreateContactFieldCol does
+func htmlContactFieldFor(field utils.ContactField, cfg *utils.Config, content *utils.Content) htmlContactField {
+	result := htmlContactField{Text: utils.ResolveTemplate(field.Content, content)}
+	if result.Text == "" {
+		return result
+	}
+	if field.Type != nil && *field.Type == "link" && field.Link != nil {
+		result.Link = utils.ResolveTemplate(*field.Link, content)
+	}
+	// Like the PDF, unmapped contact icons are simply left out
+	if _, mapped := cfg.Icons.Mappings[field.Icon]; mapped {
+		result.Icon = htmlIcon(field.Icon, cfg)
+	}
+	return result
+}
+
+// htmlIcon inlines a mapped icon's SVG, colored and sized by the .icon class
+func htmlIcon(iconName string, cfg *utils.Config) template.HTML {
+	svg, err := utils.IconSVG(iconName, &cfg.Icons)
+	if err == nil && !strings.Contains(svg, "<svg") {
+		err = fmt.Errorf("no <svg> element")
+	}
+	if err != nil {
+		utils.Warn(utils.Problem{Code: "icon-unavailable", Icon: iconName, Message: fmt.Sprintf("could not inline icon: %v; leaving it blank", err)})
+		return ""
+	}
+	svg = svg[strings.Index(svg, "<svg")+len("<svg"):]
+	return template.HTML(`<svg class="icon" aria-hidden="true" ` + strings.TrimSpace(svg))
+}
+
+var (
+	cssUnsafe  = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+	cssHex     = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+	cssQuoted  = strings.NewReplacer(`"`, "", `\`, "", "<", "", ">", "", "\n", "")
+	htmlFamily = map[string]string{
+		"helvetica": `Helvetica, Arial, sans-serif`,
+		"arial":     `Helvetica, Arial, sans-serif`,
+		"times":     `"Times New Roman", Times, serif`,
+		"courier":   `"Courier New", Courier, monospace`,
+	}
+)
+
+// fontClass is the CSS class of a configured font
+func fontClass(name string) string {
+	return "font-" + cssUnsafe.ReplaceAllString(name, "-")
+}
+
+// spaceClass is the CSS class giving an element the height of a row with
+// the named spacing. Tight rows are the 2mm shorter ones used for entry
+// details and bullets.
+func spaceClass(name string, tight bool) string {
+	class := "space-" + cssUnsafe.ReplaceAllString(name, "-")
+	if tight {
+		class += "-tight"
+	}
+	return class
+}
+
+// cssColor refers to a named color's variable, or uses a hex color as is.
+// Anything else is black, as in the PDF.
+func cssColor(color string, colors map[string]string) string {
+	if _, named := colors[color]; named {
+		return "var(--color-" + cssUnsafe.ReplaceAllString(color, "-") + ")"
+	}
+	return hexColor(color, "#000000")
+}
+
+func hexColor(color, fallback string) string {
+	if !cssHex.MatchString(color) {
+		return fallback
+	}
+	return "#" + strings.TrimPrefix(color, "#")
+}
+
+func cssFontFamily(family string) string {
+	if stack, ok := htmlFamily[strings.ToLower(family)]; ok {
+		return stack
+	}
+	return `"` + cssQuoted.Replace(family) + `", sans-serif`
+}
+
+func cssFontStyle(style fontstyle.Type) (weight, slant string) {
+	weight, slant = "normal", "normal"
+	if style == fontstyle.Bold || style == fontstyle.BoldItalic {
+		weight = "bold"
+	}
+	if style == fontstyle.Italic || style == fontstyle.BoldItalic {
+		slant = "italic"
+	}
+	return weight, slant
+}
+
+// htmlStyle builds the stylesheet: colors as variables, a class per font
+// and spacing, and the page box from the PDF margins
+func htmlStyle(cfg *utils.Config) template.CSS {
+	var css strings.Builder
+
+	css.WriteString(":root {\n")
+	for _, name := range utils.SortedKeys(cfg.Colors) {
+		fmt.Fprintf(&css, "  --color-%s: %s;\n", cssUnsafe.ReplaceAllString(name, "-"), hexColor(cfg.Colors[name], "#000000"))
+	}
+	css.WriteString("}\n")
+
+	margins := cfg.PDF.Margins
+	fmt.Fprintf(&css, "body { margin: 0; background: %s; }\n", hexColor(cfg.PDF.BackgroundColor, "#ffffff"))
+	fmt.Fprintf(&css, ".page { box-sizing: border-box; max-width: 210mm; margin: 0 auto; padding: %gmm %gmm %gmm %gmm; }\n",
+		margins.Top, margins.Right, margins.Bottom, margins.Left)
+
+	for _, name := range utils.SortedKeys(cfg.Fonts) {
+		font := cfg.Fonts[name]
+		weight, slant := cssFontStyle(utils.ResolveFontStyle(font.Style))
+		fmt.Fprintf(&css, ".%s { --size: %gpt; font-family: %s; font-size: var(--size); font-weight: %s; font-style: %s; color: %s; }\n",
+			fontClass(name), font.Size, cssFontFamily(font.Family), weight, slant, cssColor(font.Color, cfg.Colors))
+	}
+	css.WriteString(".minor { font-size: calc(var(--size) - 1pt); }\n")
+
+	for _, name := range utils.SortedKeys(cfg.Spacing) {
+		spacing := cfg.Spacing[name]
+		fmt.Fprintf(&css, ".%s { min-height: %gmm; }\n", spaceClass(name, false), spacing)
+		fmt.Fprintf(&css, ".%s { min-height: %gmm; }\n", spaceClass(name, true), max(spacing-2, 0))
+	}
+
+	fmt.Fprintf(&css, ".icon { width: 1.25em; height: 1em; vertical-align: -0.125em; fill: %s; }\n", hexColor(utils.ResolveIconColor(&cfg.Icons, cfg.Colors), "#000000"))
+	fmt.Fprintf(&css, ".contact a { color: %s; }\n", cssColor("link", cfg.Colors))
+	fmt.Fprintf(&css, ".divider hr { border: 0; border-top: 1pt solid %s; margin: 0; flex: 1; }\n", cssColor("secondary", cfg.Colors))
+	css.WriteString(htmlBaseStyle)
+	return template.CSS(css.String())
+}
+
+// htmlBaseStyle lays the page out like the PDF rows: text at the top of
+// each row, continuation lines and bullets indented 5mm
+const htmlBaseStyle = `h1, h2, h3, p, ul { margin: 0; padding: 0; }
+a { color: inherit; }
+.contact { display: grid; grid-template-columns: repeat(3, 1fr); list-style: none; }
+.contact li { padding-top: 1mm; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+.contact .icon, .section-title .icon { margin-right: 1.5mm; }
+.divider { display: flex; align-items: center; }
+.entry-location { font-style: italic; }
+.bullets { list-style: none; padding-left: 5mm; }
+.bullets li::before { content: "- "; }
+.citation { padding-top: 2mm; padding-left: 5mm; text-indent: -5mm; }
+@media print { .page { max-width: none; } @page { size: A4; margin: 0; } }
+`
+
+var htmlTemplate = template.Must(template.New("resume").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}</style>
+</head>
+<body>
+<main class="page">
+{{- with .Header}}
+<header><h1 class="{{.Class}}">{{.Text}}</h1></header>
+{{- end}}
+{{- with .Contact}}
+<ul class="contact {{.Class}}">
+{{- range .Fields}}
+  <li>{{.Icon}}{{if .Link}}<a href="{{.Link}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}</li>
+{{- end}}
+</ul>
+<div class="divider {{.DividerClass}}"><hr></div>
+{{- end}}
+{{- range .Sections}}
+<section class="section-{{.Key}}">
+  <h2 class="section-title {{.Title.Class}}">{{.Icon}}{{.Title.Text}}</h2>
+{{- if .Entries}}
+  <div class="{{.BodyClass}}">
+{{- range .Entries}}
+  {{with .Heading}}<h3 class="{{.Class}}">{{.Text}}</h3>{{end}}
+  {{- with .Location}}<p class="entry-location {{.Class}}">{{.Text}}</p>{{end}}
+  {{- if .Bullets}}
+  <ul class="bullets">
+{{- $class := .BulletClass}}
+{{- range .Bullets}}
+    <li class="{{$class}}">{{.}}</li>
+{{- end}}
+  </ul>
+{{- end}}
+{{- end}}
+  </div>
+{{- else if .Citations}}
+{{- range .Citations}}
+  <p class="citation {{.Class}}">
+{{- range .Runs}}
+{{- if .Link}}<a href="{{.Link}}">{{end}}
+{{- if .Bold}}<strong>{{end}}{{if .Italic}}<em>{{end}}{{.Text}}{{if .Italic}}</em>{{end}}{{if .Bold}}</strong>{{end}}
+{{- if .Link}}</a>{{end}}
+{{- end}}</p>
+{{- end}}
+{{- else if .Body}}
+  <p class="{{.Body.Class}}">{{.Body.Text}}</p>
+{{- end}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,278 @@
+package templates
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"resume-builder/utils"
+)
+
+// latexClass is the bundled class the generated document is set with. It
+// is written into the document with filecontents, so the .tex compiles on
+// its own.
+//
+//go:embed resumebuilder.cls
+var latexClass string
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// latexURLEscaper escapes the characters \href can't take as they are
+var latexURLEscaper = strings.NewReplacer(`\`, `\\`, `#`, `\#`, `%`, `\%`, `{`, `\{`, `}`, `\}`)
+
+var latexNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// BuildLaTeX renders the resume as a LaTeX document set with the bundled
+// resumebuilder class, following the section order of BuildTemplate1. The
+// config colors become xcolor colors and the fonts fontspec families and
+// font switches, so the output can be typeset by hand with XeLaTeX or
+// LuaLaTeX.
+func BuildLaTeX(cfg *utils.Config, content *utils.Content) ([]byte, error) {
+	var out strings.Builder
+	out.WriteString("% Generated by resume-builder. Compile with xelatex or lualatex.\n")
+	out.WriteString("\\begin{filecontents*}[overwrite]{resumebuilder.cls}\n")
+	out.WriteString(latexClass)
+	out.WriteString("\\end{filecontents*}\n")
+	out.WriteString("\\documentclass{resumebuilder}\n\n")
+	writeLaTeXPreamble(&out, cfg, content)
+
+	out.WriteString("\n\\begin{document}\n")
+
+	if cfg.Sections["header"].Enabled && content.Personal.Name != "" {
+		fmt.Fprintf(&out, "\\resumeheader{%s}\n", latexEscape(content.Personal.Name))
+	}
+
+	if cfg.Sections["contact"].Enabled {
+		writeLaTeXContacts(&out, content)
+	}
+
+	for _, sectionKey := range utils.SectionOrder {
+		sectionCfg, exists := cfg.Sections[sectionKey]
+		if !exists || !sectionCfg.Enabled {
+			continue
+		}
+
+		sectionData, hasData := content.Sections[sectionKey]
+		if !hasData {
+			continue
+		}
+
+		fmt.Fprintf(&out, "\n\\resumesection{%s}\n", latexEscape(sectionCfg.Title))
+
+		switch sectionCfg.Template {
+		case "simple_list":
+			if bodyText, ok := simpleListText(sectionData); ok {
+				fmt.Fprintf(&out, "{\\resumebodyfont %s\\par}\n", latexEscape(bodyText))
+			}
+		case "publications":
+			citations, err := utils.LoadCitations(content, sectionData, sectionCfg.Style)
+			if err != nil {
+				return nil, err
+			}
+			if len(citations) == 0 {
+				continue
+			}
+			out.WriteString("\\begin{resumecitations}\n")
+			for _, citation := range citations {
+				out.WriteString("\\item ")
+				for _, run := range citation.Runs {
+					out.WriteString(latexRun(run))
+				}
+				out.WriteString("\n")
+			}
+			out.WriteString("\\end{resumecitations}\n")
+		case "entry_list":
+			for _, item := range entryListData(sectionData).Items {
+				heading, location := entryHeading(item), entryLocationLine(item)
+				if heading != "" || location != "" {
+					fmt.Fprintf(&out, "\\resumeentry{%s}{%s}\n", latexEscape(heading), latexEscape(location))
+				}
+				if len(item.Description) == 0 {
+					continue
+				}
+				out.WriteString("\\begin{resumebullets}\n")
+				for _, bullet := range item.Description {
+					fmt.Fprintf(&out, "  \\item %s\n", latexEscape(bullet))
+				}
+				out.WriteString("\\end{resumebullets}\n")
+			}
+		}
+	}
+
+	out.WriteString("\\end{document}\n")
+	return []byte(out.String()), nil
+}
+
+// writeLaTeXPreamble sets the page, the colors and the font switches of the
+// class from the config
+func writeLaTeXPreamble(out *strings.Builder, cfg *utils.Config, content *utils.Content) {
+	margins := cfg.PDF.Margins
+	fmt.Fprintf(out, "\\geometry{a4paper, top=%gmm, bottom=%gmm, left=%gmm, right=%gmm}\n",
+		margins.Top, margins.Bottom, margins.Left, margins.Right)
+	if name := content.Personal.Name; name != "" {
+		fmt.Fprintf(out, "\\hypersetup{pdftitle={%s}, pdfauthor={%s}}\n", latexEscape(name), latexEscape(name))
+	}
+
+	out.WriteString("\n% Colors\n")
+	for _, name := range utils.SortedKeys(cfg.Colors) {
+		fmt.Fprintf(out, "\\definecolor{%s}{HTML}{%s}\n", latexColorName(name), hexValue(cfg.Colors[name]))
+	}
+	fmt.Fprintf(out, "\\colorlet{resumedividercolor}{%s}\n", latexColorRef(cfg, "secondary"))
+	fmt.Fprintf(out, "\\definecolor{resumelinkcolor}{HTML}{%s}\n", hexValue(utils.ResolveIconColor(&utils.IconConfig{Color: "link"}, cfg.Colors)))
+
+	// One fontspec family per distinct configured family
+	out.WriteString("\n% Fonts\n")
+	families := make(map[string]string)
+	for _, name := range utils.SortedKeys(cfg.Fonts) {
+		family := latexFamily(cfg.Fonts[name].Family)
+		if family == "" || families[family] != "" {
+			continue
+		}
+		families[family] = "\\resumefamily" + latexAlphaIndex(len(families))
+		fmt.Fprintf(out, "\\newfontfamily%s{%s}\n", families[family], latexEscape(family))
+	}
+
+	switches := []struct {
+		command   string
+		font      string
+		sizeDelta float64
+		italic    bool
+	}{
+		{"resumeheaderfont", cfg.SectionTemplates["header"].Font, 0, false},
+		{"resumecontactfont", cfg.SectionTemplates["contact"].Font, 0, false},
+		{"resumesectionfont", "section_title", 0, false},
+		{"resumeentryfont", "emphasis", 0, false},
+		{"resumebodyfont", "body", 0, false},
+		{"resumesmallfont", "body", -1, false},
+		{"resumedetailsfont", "body", -1, true},
+	}
+	for _, sw := range switches {
+		font, ok := cfg.Fonts[sw.font]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(out, "\\renewcommand{\\%s}{%s}\n", sw.command, latexFontSwitch(cfg, font, families, sw.sizeDelta, sw.italic))
+	}
+}
+
+// writeLaTeXContacts writes the contact fields three to a row, like the
+// PDF, with links as hyperlinks
+func writeLaTeXContacts(out *strings.Builder, content *utils.Content) {
+	var fields []string
+	for _, field := range content.ContactFields {
+		text := utils.ResolveTemplate(field.Content, content)
+		if text == "" {
+			continue
+		}
+		if field.Type != nil && *field.Type == "link" && field.Link != nil {
+			fields = append(fields, latexRun(utils.TextRun{Text: text, Link: utils.ResolveTemplate(*field.Link, content)}))
+		} else {
+			fields = append(fields, latexEscape(text))
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	var rows []string
+	for start := 0; start < len(fields); start += 3 {
+		end := min(start+3, len(fields))
+		rows = append(rows, strings.Join(fields[start:end], "\\resumecontactsep "))
+	}
+	out.WriteString("\\begin{resumecontact}\n")
+	out.WriteString(strings.Join(rows, "\\\\\n"))
+	out.WriteString("\n\\end{resumecontact}\n")
+}
+
+// latexRun writes a run of citation or contact text with its formatting
+func latexRun(run utils.TextRun) string {
+	text := latexEscape(run.Text)
+	if run.Italic {
+		text = "\\emph{" + text + "}"
+	}
+	if run.Bold {
+		text = "\\textbf{" + text + "}"
+	}
+	if run.Link != "" {
+		text = "\\resumelink{" + latexURLEscaper.Replace(run.Link) + "}{" + text + "}"
+	}
+	return text
+}
+
+// latexFontSwitch selects a configured font: its family, size, weight,
+// shape and color
+func latexFontSwitch(cfg *utils.Config, font utils.FontDefinition, families map[string]string, sizeDelta float64, italic bool) string {
+	var sw strings.Builder
+	if family := families[latexFamily(font.Family)]; family != "" {
+		sw.WriteString(family)
+	}
+	if font.Size > 0 {
+		size := font.Size + sizeDelta
+		fmt.Fprintf(&sw, "\\fontsize{%gpt}{%gpt}\\selectfont", size, math.Round(size*12)/10)
+	}
+	style := strings.ToLower(font.Style)
+	if style == "bold" || style == "bolditalic" {
+		sw.WriteString("\\bfseries")
+	}
+	if italic || style == "italic" || style == "bolditalic" {
+		sw.WriteString("\\itshape")
+	}
+	fmt.Fprintf(&sw, "\\color{%s}", latexColorRef(cfg, font.Color))
+	return sw.String()
+}
+
+// latexColorRef names a config color as the preamble defines it, or spells
+// out a hex value
+func latexColorRef(cfg *utils.Config, color string) string {
+	if _, ok := cfg.Colors[color]; ok {
+		return latexColorName(color)
+	}
+	c := utils.ResolveColor(color, cfg.Colors)
+	return fmt.Sprintf("rgb,255:red,%d;green,%d;blue,%d", c.Red, c.Green, c.Blue)
+}
+
+// latexColorName is the xcolor name of a config color
+func latexColorName(name string) string {
+	return "resume" + latexNameUnsafe.ReplaceAllString(name, "")
+}
+
+// latexFamily names the font fontspec should load. The PDF core fonts map
+// to the TeX Gyre fonts every TeX distribution ships.
+func latexFamily(family string) string {
+	switch strings.ToLower(family) {
+	case "helvetica", "arial":
+		return "TeX Gyre Heros"
+	case "times":
+		return "TeX Gyre Termes"
+	case "courier":
+		return "TeX Gyre Cursor"
+	}
+	return family
+}
+
+// latexAlphaIndex spells i in letters, since control sequence names can't
+// hold digits: 0 is A, 25 is Z, 26 is BA
+func latexAlphaIndex(i int) string {
+	name := string(rune('A' + i%26))
+	for i /= 26; i > 0; i /= 26 {
+		name = string(rune('A'+i%26)) + name
+	}
+	return name
+}
+
+func latexEscape(text string) string {
+	return latexEscaper.Replace(text)
+}
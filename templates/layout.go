@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"github.com/johnfercher/maroto/v2/pkg/core"
+)
+
+// SectionMarker is implemented by Maroto wrappers that want to know which
+// section the rows that follow belong to
+type SectionMarker interface {
+	BeginSection(name string)
+}
+
+// beginSection tells mrt, if it cares, that a new section starts
+func beginSection(mrt core.Maroto, name string) {
+	if marker, ok := mrt.(SectionMarker); ok {
+		marker.BeginSection(name)
+	}
+}
+
+// LayoutRow is one row added through AddRow and where it landed
+type LayoutRow struct {
+	Section string
+	Height  float64
+	Page    int
+	Top     float64 // offset from the top margin of its page
+}
+
+// LayoutReport describes how the rows of a resume were laid out
+type LayoutReport struct {
+	Rows       []LayoutRow
+	Pages      int
+	PageHeight float64 // usable height between the top and bottom margins
+	Remaining  float64 // free space left on the last page
+}
+
+// LayoutRecorder wraps a Maroto document and records every row the
+// template adds. Page breaks are simulated the way maroto places rows:
+// a row that doesn't fit in the rest of the page starts a new one.
+type LayoutRecorder struct {
+	core.Maroto
+
+	pageHeight float64
+	section    string
+	rows       []LayoutRow
+	added      []core.Row // the maroto row behind each entry of rows
+	page       int
+	used       float64
+}
+
+// NewLayoutRecorder records the layout of rows added to mrt
+func NewLayoutRecorder(mrt core.Maroto) *LayoutRecorder {
+	cfg := mrt.GetCurrentConfig()
+	return &LayoutRecorder{
+		Maroto:     mrt,
+		pageHeight: cfg.Dimensions.Height - cfg.Margins.Top - cfg.Margins.Bottom,
+		page:       1,
+	}
+}
+
+// BeginSection labels the rows that follow
+func (r *LayoutRecorder) BeginSection(name string) {
+	r.section = name
+}
+
+// AddRow adds the row to the wrapped document and records its placement
+func (r *LayoutRecorder) AddRow(rowHeight float64, cols ...core.Col) core.Row {
+	if r.used+rowHeight > r.pageHeight {
+		r.page++
+		r.used = 0
+	}
+
+	r.rows = append(r.rows, LayoutRow{
+		Section: r.section,
+		Height:  rowHeight,
+		Page:    r.page,
+		Top:     r.used,
+	})
+	r.used += rowHeight
+
+	row := r.Maroto.AddRow(rowHeight, cols...)
+	r.added = append(r.added, row)
+	return row
+}
+
+// Report summarizes the recorded layout
+func (r *LayoutRecorder) Report() LayoutReport {
+	return LayoutReport{
+		Rows:       r.rows,
+		Pages:      r.page,
+		PageHeight: r.pageHeight,
+		Remaining:  r.pageHeight - r.used,
+	}
+}
@@ -0,0 +1,33 @@
+package templates
+
+import (
+	"strings"
+
+	"resume-builder/utils"
+)
+
+// DocumentTitle is the title the PDF reports: the configured pattern with
+// the content filled in, by default the name followed by "Resume"
+func DocumentTitle(cfg *utils.Config, content *utils.Content) string {
+	if cfg.PDF.Title != "" {
+		return utils.ResolveTemplate(cfg.PDF.Title, content)
+	}
+	if content.Personal.Name == "" {
+		return "Resume"
+	}
+	return content.Personal.Name + " - Resume"
+}
+
+// DocumentSubject is the subject the PDF reports, by default "Resume"
+func DocumentSubject(cfg *utils.Config, content *utils.Content) string {
+	if cfg.PDF.Subject != "" {
+		return utils.ResolveTemplate(cfg.PDF.Subject, content)
+	}
+	return "Resume"
+}
+
+// DocumentKeywords lists the skills, comma separated, for the PDF keywords
+// document systems and search index
+func DocumentKeywords(content *utils.Content) string {
+	return strings.Join(simpleListItems(content.Sections["skills"]), ", ")
+}
@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+
+	"resume-builder/utils"
+)
+
+// buildPublicationsSection lists the entries of a BibTeX file in the
+// section's citation style, wrapping each citation with a hanging indent
+func buildPublicationsSection(mrt core.Maroto, cfg *utils.Config, sectionCfg utils.SectionConfig, sectionData interface{}, content *utils.Content) error {
+	citations, err := utils.LoadCitations(content, sectionData, sectionCfg.Style)
+	if err != nil {
+		return err
+	}
+
+	template := cfg.SectionTemplates[sectionCfg.Template]
+	err = utils.AddSectionTitle(mrt, cfg, sectionCfg, template)
+	if err != nil {
+		return err
+	}
+
+	measurer := newTextMeasurer()
+
+	bodyFont := cfg.Fonts[template.Font]
+	bodyColor := utils.ResolveColor(bodyFont.Color, cfg.Colors)
+	itemSpacing := cfg.Spacing[template.ItemSpacing]
+	base := props.Text{
+		Family: bodyFont.Family,
+		Size:   bodyFont.Size - 1,
+		Style:  utils.ResolveFontStyle(bodyFont.Style),
+		Color:  &bodyColor,
+	}
+
+	page := mrt.GetCurrentConfig()
+	width := page.Dimensions.Width - page.Margins.Left - page.Margins.Right
+
+	for _, citation := range citations {
+		for i, line := range measurer.richLines(citation.Runs, base, width, 5.0) {
+			// The first line is as tall as an entry heading, which spaces
+			// the citations apart; the rest are as tall as bullet lines
+			height, top := itemSpacing-2, 0.0
+			if i == 0 {
+				height, top = itemSpacing, 2.0
+			}
+			mrt.AddRow(height, measurer.richCol(line, top))
+		}
+	}
+
+	return nil
+}
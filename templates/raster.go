@@ -0,0 +1,367 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/breakline"
+	"github.com/johnfercher/maroto/v2/pkg/consts/extension"
+	"github.com/johnfercher/maroto/v2/pkg/consts/linestyle"
+	"github.com/johnfercher/maroto/v2/pkg/consts/orientation"
+	"github.com/johnfercher/maroto/v2/pkg/core/entity"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+	"golang.org/x/image/font"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const pointsPerMM = 72.0 / 25.4
+
+// Rasterize draws every page of the recorded layout as an image at dpi.
+// The rows are the ones the PDF is generated from, placed where maroto
+// places them, so the images match the PDF page for page.
+func (r *LayoutRecorder) Rasterize(dpi float64) ([]image.Image, error) {
+	if dpi <= 0 {
+		return nil, fmt.Errorf("dpi must be positive, got %g", dpi)
+	}
+
+	cfg := r.GetCurrentConfig()
+	provider, err := newRasterProvider(cfg, dpi)
+	if err != nil {
+		return nil, err
+	}
+
+	width := int(math.Round(provider.px(cfg.Dimensions.Width)))
+	height := int(math.Round(provider.px(cfg.Dimensions.Height)))
+	root := entity.Cell{
+		Width:  cfg.Dimensions.Width - cfg.Margins.Left - cfg.Margins.Right,
+		Height: r.pageHeight,
+	}
+
+	var pages []image.Image
+	for page := 1; page <= r.page; page++ {
+		provider.dc = gg.NewContext(width, height)
+		provider.dc.SetRGB(1, 1, 1)
+		provider.dc.Clear()
+
+		for i, row := range r.rows {
+			if row.Page != page {
+				continue
+			}
+			cell := root
+			cell.Y = row.Top
+			r.added[i].Render(provider, cell)
+		}
+
+		if number := cfg.PageNumber; number != nil {
+			provider.AddText(number.GetPageString(page, r.page), &root, number.GetNumberTextProp(root.Height))
+		}
+
+		pages = append(pages, provider.dc.Image())
+	}
+
+	return pages, nil
+}
+
+// rasterProvider draws maroto components onto a gg context. It implements
+// core.Provider the way maroto's gofpdf provider does for the components
+// the templates use; rows and columns need no drawing of their own, since
+// the templates set no cell styles. Text is broken and aligned with the
+// PDF font metrics and set in the Go fonts, scaled to the width the PDF
+// font gives it.
+type rasterProvider struct {
+	dc       *gg.Context
+	dpi      float64
+	margins  *entity.Margins
+	measurer *textMeasurer
+	fonts    map[goFontKey]*truetype.Font
+	faces    map[string]font.Face
+	images   map[string]image.Image
+}
+
+func newRasterProvider(cfg *entity.Config, dpi float64) (*rasterProvider, error) {
+	p := &rasterProvider{
+		dpi:      dpi,
+		margins:  cfg.Margins,
+		measurer: newTextMeasurer(),
+		fonts:    make(map[goFontKey]*truetype.Font),
+		faces:    make(map[string]font.Face),
+		images:   make(map[string]image.Image),
+	}
+	for key, data := range goFontData {
+		parsed, err := truetype.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("loading preview font: %w", err)
+		}
+		p.fonts[key] = parsed
+	}
+	return p, nil
+}
+
+// px converts millimeters to pixels
+func (p *rasterProvider) px(mm float64) float64 {
+	return mm * p.dpi / 25.4
+}
+
+func (p *rasterProvider) CreateRow(height float64) {}
+
+func (p *rasterProvider) CreateCol(width, height float64, config *entity.Config, prop *props.Cell) {}
+
+func (p *rasterProvider) AddText(value string, cell *entity.Cell, prop *props.Text) {
+	value = rasterText(value, prop)
+	fontHeight := prop.Size / pointsPerMM
+
+	left, right, top := math.Min(prop.Left, cell.Width), math.Min(prop.Right, cell.Width), math.Min(prop.Top, cell.Height)
+	width := math.Max(cell.Width-left-right, 0)
+	x := cell.X + left
+	y := cell.Y + top + fontHeight
+
+	color := prop.Color
+	if prop.Hyperlink != nil {
+		color = &props.BlueColor
+	}
+	if color != nil {
+		p.dc.SetRGB255(color.Red, color.Green, color.Blue)
+	} else {
+		p.dc.SetRGB(0, 0, 0)
+	}
+	p.dc.SetFontFace(p.face(prop))
+
+	for i, line := range p.textLines(value, prop, width) {
+		lineWidth := p.measurer.width(line, *prop)
+		lineX := x
+		switch prop.Align {
+		case align.Right:
+			lineX += width - lineWidth
+		case align.Center:
+			lineX += (width - lineWidth) / 2
+		}
+		p.drawString(line, lineX, y+float64(i)*(fontHeight+prop.VerticalPadding), lineWidth)
+	}
+}
+
+// textLines breaks text the way maroto does for a column of width mm
+func (p *rasterProvider) textLines(value string, prop *props.Text, width float64) []string {
+	if p.measurer.width(value, *prop) < width {
+		return []string{value}
+	}
+
+	var lines []string
+	if prop.BreakLineStrategy == breakline.DashStrategy {
+		dash := p.measurer.width(" - ", *prop)
+		line, used := "", 0.0
+		for _, letter := range value {
+			if used+dash > width-dash {
+				lines = append(lines, line+"-")
+				line, used = "", 0
+			}
+			line += string(letter)
+			used += p.measurer.width(string(letter), *prop)
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	lines, used := []string{""}, 0.0
+	for _, word := range strings.Split(value, " ") {
+		wordWidth := p.measurer.width(word+" ", *prop)
+		if used+wordWidth < width {
+			lines[len(lines)-1] += word + " "
+			used += wordWidth
+		} else {
+			lines = append(lines, word+" ")
+			used = wordWidth
+		}
+	}
+	return lines
+}
+
+// drawString sets text with its baseline at x, y mm, scaled horizontally to
+// width mm
+func (p *rasterProvider) drawString(text string, x, y, width float64) {
+	px, py := p.px(p.margins.Left+x), p.px(p.margins.Top+y)
+	drawn, _ := p.dc.MeasureString(text)
+
+	p.dc.Push()
+	if drawn > 0 && width > 0 {
+		p.dc.ScaleAbout(p.px(width)/drawn, 1, px, py)
+	}
+	p.dc.DrawString(text, px, py)
+	p.dc.Pop()
+}
+
+// face returns the Go font face standing in for the font of prop
+func (p *rasterProvider) face(prop *props.Text) font.Face {
+	key := goFontFor(prop.Family, prop.Style)
+	name := fmt.Sprintf("%v/%s/%g", key.mono, key.style, prop.Size)
+	if face, ok := p.faces[name]; ok {
+		return face
+	}
+	face := truetype.NewFace(p.fonts[key], &truetype.Options{Size: prop.Size, DPI: p.dpi, Hinting: font.HintingFull})
+	p.faces[name] = face
+	return face
+}
+
+// rasterText undoes the code page translation of coreText: text for the
+// core fonts may reach the provider in Windows-1252 rather than UTF-8
+func rasterText(value string, prop *props.Text) string {
+	if !isCoreFont(prop.Family) || utf8.ValidString(value) {
+		return value
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().String(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func (p *rasterProvider) GetFontHeight(prop *props.Font) float64 {
+	return prop.Size / pointsPerMM
+}
+
+func (p *rasterProvider) GetLinesQuantity(text string, textProp *props.Text, colWidth float64) int {
+	return len(p.textLines(rasterText(text, textProp), textProp, colWidth))
+}
+
+func (p *rasterProvider) AddLine(cell *entity.Cell, prop *props.Line) {
+	var x1, y1, x2, y2 float64
+	if prop.Orientation == orientation.Vertical {
+		size := cell.Height * prop.SizePercent / 100
+		space := (cell.Height - size) / 2
+		x1 = cell.X + cell.Width*prop.OffsetPercent/100
+		x2, y1, y2 = x1, cell.Y+space, cell.Y+cell.Height-space
+	} else {
+		size := cell.Width * prop.SizePercent / 100
+		space := (cell.Width - size) / 2
+		y1 = cell.Y + cell.Height*prop.OffsetPercent/100
+		y2, x1, x2 = y1, cell.X+space, cell.X+cell.Width-space
+	}
+
+	if prop.Color != nil {
+		p.dc.SetRGB255(prop.Color.Red, prop.Color.Green, prop.Color.Blue)
+	} else {
+		p.dc.SetRGB(0, 0, 0)
+	}
+	p.dc.SetLineWidth(p.px(prop.Thickness))
+	if prop.Style != linestyle.Solid {
+		p.dc.SetDash(p.px(1), p.px(1))
+	} else {
+		p.dc.SetDash()
+	}
+
+	p.dc.DrawLine(p.px(p.margins.Left+x1), p.px(p.margins.Top+y1), p.px(p.margins.Left+x2), p.px(p.margins.Top+y2))
+	p.dc.Stroke()
+}
+
+func (p *rasterProvider) AddImageFromFile(value string, cell *entity.Cell, prop *props.Rect) {
+	img, err := p.loadImage(value)
+	if err != nil {
+		return
+	}
+	p.drawImage(img, cell, prop)
+}
+
+func (p *rasterProvider) AddImageFromBytes(data []byte, cell *entity.Cell, prop *props.Rect, extension extension.Type) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	p.drawImage(img, cell, prop)
+}
+
+func (p *rasterProvider) AddBackgroundImageFromBytes(data []byte, cell *entity.Cell, prop *props.Rect, extension extension.Type) {
+	p.AddImageFromBytes(data, cell, prop, extension)
+}
+
+// drawImage sizes and places an image in its cell like maroto does
+func (p *rasterProvider) drawImage(img image.Image, cell *entity.Cell, prop *props.Rect) {
+	bounds := img.Bounds()
+	ratio := float64(bounds.Dy()) / float64(bounds.Dx())
+
+	width := cell.Width * prop.Percent / 100
+	if ratio > cell.Height/cell.Width && !prop.JustReferenceWidth {
+		width = cell.Height / ratio * prop.Percent / 100
+	}
+	height := width * ratio
+	if prop.JustReferenceWidth && height > cell.Height {
+		width = cell.Height / ratio
+		height = width * ratio
+	}
+
+	x, y := prop.Left, prop.Top
+	if prop.Center {
+		x, y = (cell.Width-width)/2, (cell.Height-height)/2
+	}
+
+	p.dc.Push()
+	p.dc.Translate(p.px(p.margins.Left+cell.X+x), p.px(p.margins.Top+cell.Y+y))
+	p.dc.Scale(p.px(width)/float64(bounds.Dx()), p.px(height)/float64(bounds.Dy()))
+	p.dc.DrawImage(img, -bounds.Min.X, -bounds.Min.Y)
+	p.dc.Pop()
+}
+
+// loadImage reads an image file once per preview
+func (p *rasterProvider) loadImage(filename string) (image.Image, error) {
+	if img, ok := p.images[filename]; ok {
+		return img, nil
+	}
+	img, err := gg.LoadImage(filename)
+	if err != nil {
+		return nil, err
+	}
+	p.images[filename] = img
+	return img, nil
+}
+
+func (p *rasterProvider) GetDimensionsByImage(file string) (*entity.Dimensions, error) {
+	img, err := p.loadImage(file)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Dimensions{Width: float64(img.Bounds().Dx()), Height: float64(img.Bounds().Dy())}, nil
+}
+
+func (p *rasterProvider) GetDimensionsByImageByte(data []byte, extension extension.Type) (*entity.Dimensions, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Dimensions{Width: float64(config.Width), Height: float64(config.Height)}, nil
+}
+
+// Codes, protection and metadata don't show up in a preview
+
+var errRasterUnsupported = errors.New("not supported in the preview")
+
+func (p *rasterProvider) AddMatrixCode(code string, cell *entity.Cell, prop *props.Rect) {}
+
+func (p *rasterProvider) AddQrCode(code string, cell *entity.Cell, rect *props.Rect) {}
+
+func (p *rasterProvider) AddBarCode(code string, cell *entity.Cell, prop *props.Barcode) {}
+
+func (p *rasterProvider) GetDimensionsByMatrixCode(code string) (*entity.Dimensions, error) {
+	return nil, errRasterUnsupported
+}
+
+func (p *rasterProvider) GetDimensionsByQrCode(code string) (*entity.Dimensions, error) {
+	return nil, errRasterUnsupported
+}
+
+func (p *rasterProvider) GenerateBytes() ([]byte, error) {
+	return nil, errRasterUnsupported
+}
+
+func (p *rasterProvider) SetProtection(protection *entity.Protection) {}
+
+func (p *rasterProvider) SetCompression(compression bool) {}
+
+func (p *rasterProvider) SetMetadata(metadata *entity.Metadata) {}
@@ -0,0 +1,143 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/johnfercher/maroto/v2/pkg/components/col"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontfamily"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+	"github.com/jung-kurt/gofpdf"
+
+	"resume-builder/utils"
+)
+
+// runPieces splits text into words that keep their trailing spaces
+var runPieces = regexp.MustCompile(`\S+\s*|\s+`)
+
+// textMeasurer measures text with the same font metrics maroto renders with
+type textMeasurer struct {
+	pdf       *gofpdf.Fpdf
+	translate func(string) string
+	loaded    map[goFontKey]bool // Go fonts added to pdf so far
+}
+
+func newTextMeasurer() *textMeasurer {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	return &textMeasurer{pdf: pdf, translate: pdf.UnicodeTranslatorFromDescriptor(""), loaded: make(map[goFontKey]bool)}
+}
+
+func (m *textMeasurer) width(value string, prop props.Text) float64 {
+	if isGoFamily(prop.Family) {
+		m.loadGoFont(prop)
+	}
+	m.pdf.SetFont(prop.Family, string(prop.Style), prop.Size)
+	if isCoreFont(prop.Family) {
+		value = m.translate(value)
+	}
+	return m.pdf.GetStringWidth(value)
+}
+
+// loadGoFont adds the embedded Go font of prop the first time it's measured
+func (m *textMeasurer) loadGoFont(prop props.Text) {
+	key := goFontFor(prop.Family, prop.Style)
+	if m.loaded[key] {
+		return
+	}
+	m.pdf.AddUTF8FontFromBytes(prop.Family, string(key.style), goFontData[key])
+	m.loaded[key] = true
+}
+
+// coreText prepares text for maroto. Core fonts need the text in their code
+// page, which maroto only converts to when the family name is lower case,
+// so accents and dashes in citations would otherwise print as mojibake.
+func (m *textMeasurer) coreText(value string, prop props.Text) string {
+	if isCoreFont(prop.Family) && prop.Family != strings.ToLower(prop.Family) {
+		return m.translate(value)
+	}
+	return value
+}
+
+func isCoreFont(family string) bool {
+	switch strings.ToLower(family) {
+	case fontfamily.Arial, fontfamily.Helvetica, fontfamily.Symbol, fontfamily.ZapBats, fontfamily.Courier:
+		return true
+	}
+	return false
+}
+
+// richSegment is a run of text placed on a line
+type richSegment struct {
+	text string
+	prop props.Text
+	left float64
+}
+
+// richLines wraps runs into lines no wider than maxWidth, continuation lines
+// indented by indent. base gives the font; runs add bold, italic and links.
+func (m *textMeasurer) richLines(runs []utils.TextRun, base props.Text, maxWidth, indent float64) [][]richSegment {
+	var lines [][]richSegment
+	var line []richSegment
+	x, start := 0.0, 0.0
+
+	for _, run := range runs {
+		prop := runProp(base, run)
+		for _, piece := range runPieces.FindAllString(run.Text, -1) {
+			word := strings.TrimRight(piece, " ")
+			// Text that would touch the edge wraps, since maroto wraps it otherwise
+			if len(line) > 0 && start+x+m.width(word, prop) >= maxWidth-0.5 {
+				lines = append(lines, line)
+				line, x, start = nil, 0, indent
+				if word == "" {
+					continue
+				}
+				piece = strings.TrimLeft(piece, " ")
+			}
+
+			if last := len(line) - 1; last >= 0 && line[last].prop == prop {
+				line[last].text += piece
+			} else {
+				line = append(line, richSegment{text: piece, prop: prop, left: start + x})
+			}
+			x += m.width(piece, prop)
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func runProp(base props.Text, run utils.TextRun) props.Text {
+	prop := base
+	switch {
+	case run.Bold && run.Italic:
+		prop.Style = fontstyle.BoldItalic
+	case run.Bold:
+		prop.Style = fontstyle.Bold
+	case run.Italic:
+		prop.Style = fontstyle.Italic
+	}
+	if run.Link != "" {
+		link := run.Link
+		prop.Hyperlink = &link
+	}
+	return prop
+}
+
+// richCol places the segments of a line side by side in a full-width column
+func (m *textMeasurer) richCol(line []richSegment, top float64) core.Col {
+	column := col.New(12)
+	for _, segment := range line {
+		prop := segment.prop
+		prop.Left = segment.left
+		prop.Top = top
+		prop.Align = align.Left
+		column.Add(text.New(m.coreText(strings.TrimRight(segment.text, " "), prop), prop))
+	}
+	return column
+}
@@ -0,0 +1,186 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"resume-builder/utils"
+)
+
+// contactLabels spells out contact field names that don't just capitalize
+var contactLabels = map[string]string{"github": "GitHub", "linkedin": "LinkedIn"}
+
+// BuildText renders the resume as plain text for applicant tracking
+// systems: one column, contact fields as labeled lines instead of icons,
+// and the sections in the configured order, wrapped at the text width
+func BuildText(cfg *utils.Config, content *utils.Content) ([]byte, error) {
+	out := &textWriter{width: cfg.Text.Width}
+	if out.width == 0 {
+		out.width = utils.DefaultTextWidth
+	}
+
+	if cfg.Sections["header"].Enabled && content.Personal.Name != "" {
+		out.wrap(content.Personal.Name, "", "")
+	}
+
+	if cfg.Sections["contact"].Enabled {
+		out.blank()
+		writeTextContacts(out, cfg, content)
+	}
+
+	for _, sectionKey := range utils.SectionOrder {
+		sectionCfg, exists := cfg.Sections[sectionKey]
+		if !exists || !sectionCfg.Enabled {
+			continue
+		}
+
+		sectionData, hasData := content.Sections[sectionKey]
+		if !hasData {
+			continue
+		}
+
+		title := sectionCfg.Title
+		if title == "" {
+			title = strings.ToUpper(sectionKey)
+		}
+		out.blank()
+		out.line(title)
+		out.line(strings.Repeat("-", utf8.RuneCountInString(title)))
+
+		switch sectionCfg.Template {
+		case "simple_list":
+			// Lists are comma separated, which parsers split more reliably than " | "
+			if items := simpleListItems(sectionData); len(items) > 0 {
+				out.wrap(strings.Join(items, ", "), "", "")
+			} else if bodyText, ok := simpleListText(sectionData); ok {
+				out.wrap(bodyText, "", "")
+			}
+		case "publications":
+			citations, err := utils.LoadCitations(content, sectionData, sectionCfg.Style)
+			if err != nil {
+				return nil, err
+			}
+			for _, citation := range citations {
+				var text strings.Builder
+				for _, run := range citation.Runs {
+					text.WriteString(run.Text)
+				}
+				out.wrap(text.String(), "", "  ")
+			}
+		case "entry_list":
+			for i, item := range entryListData(sectionData).Items {
+				if i > 0 {
+					out.blank()
+				}
+				if heading := entryHeading(item); heading != "" {
+					out.wrap(heading, "", "")
+				}
+				if location := entryLocationLine(item); location != "" {
+					out.wrap(location, "", "")
+				}
+				for _, bullet := range item.Description {
+					out.wrap(bullet, "- ", "  ")
+				}
+			}
+		}
+	}
+
+	return out.buf.Bytes(), nil
+}
+
+// writeTextContacts writes one "Label: value" line per contact field, with
+// the values lined up in a column. Links are written out, since the text
+// of a link like "Portfolio" means nothing without its URL.
+func writeTextContacts(out *textWriter, cfg *utils.Config, content *utils.Content) {
+	type contactLine struct{ label, value string }
+	var lines []contactLine
+	labelWidth := cfg.Text.LabelWidth
+
+	for _, field := range content.ContactFields {
+		value := utils.ResolveTemplate(field.Content, content)
+		if value == "" {
+			continue
+		}
+		if field.Type != nil && *field.Type == "link" && field.Link != nil {
+			link := utils.ResolveTemplate(*field.Link, content)
+			if !strings.HasPrefix(link, "mailto:") && !strings.HasPrefix(link, "tel:") {
+				value = link
+			}
+		}
+
+		label := contactLabel(field.Field) + ":"
+		lines = append(lines, contactLine{label, value})
+		if cfg.Text.LabelWidth == 0 {
+			labelWidth = max(labelWidth, utf8.RuneCountInString(label)+1)
+		}
+	}
+
+	for _, line := range lines {
+		label := fmt.Sprintf("%-*s", labelWidth, line.label)
+		if !strings.HasSuffix(label, " ") {
+			label += " "
+		}
+		out.wrap(line.value, label, strings.Repeat(" ", utf8.RuneCountInString(label)))
+	}
+}
+
+func contactLabel(field string) string {
+	if label, ok := contactLabels[strings.ToLower(field)]; ok {
+		return label
+	}
+	label := strings.ReplaceAll(field, "_", " ")
+	if label == "" {
+		return "Contact"
+	}
+	first, size := utf8.DecodeRuneInString(label)
+	return strings.ToUpper(string(first)) + label[size:]
+}
+
+// textWriter collects plain-text lines, wrapping them at width
+type textWriter struct {
+	buf   bytes.Buffer
+	width int
+	// afterBlank is set once a blank line ends the output
+	afterBlank bool
+}
+
+func (w *textWriter) line(text string) {
+	w.buf.WriteString(strings.TrimRight(text, " ") + "\n")
+	w.afterBlank = false
+}
+
+// blank separates blocks with one empty line, never at the start
+func (w *textWriter) blank() {
+	if w.buf.Len() > 0 && !w.afterBlank {
+		w.buf.WriteString("\n")
+		w.afterBlank = true
+	}
+}
+
+// wrap writes text word by word, starting the first line with first and
+// the lines after it with rest. Line breaks in text are kept; words longer
+// than a line get a line of their own.
+func (w *textWriter) wrap(text, first, rest string) {
+	prefix := first
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := prefix
+		empty := true
+		for _, word := range strings.Fields(paragraph) {
+			if !empty && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > w.width {
+				w.line(line)
+				line, empty = rest, true
+			}
+			if !empty {
+				line += " "
+			}
+			line += word
+			empty = false
+		}
+		if !empty {
+			w.line(line)
+			prefix = rest
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// BibEntry is one entry of a BibTeX file, with field names lower-cased
+// and values still in LaTeX
+type BibEntry struct {
+	Type   string
+	Key    string
+	Fields map[string]string
+	Line   int
+}
+
+// bibMonths are the month macros every BibTeX style defines
+var bibMonths = map[string]string{
+	"jan": "January", "feb": "February", "mar": "March", "apr": "April",
+	"may": "May", "jun": "June", "jul": "July", "aug": "August",
+	"sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+// LoadBibTeX reads and parses a .bib file
+func LoadBibTeX(filename string) ([]BibEntry, error) {
+	data, err := ReadInput(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBibTeX(DisplayName(filename), data)
+}
+
+// ParseBibTeX parses the entries of a BibTeX document. @string macros are
+// expanded; @comment and @preamble blocks and text between entries are
+// skipped.
+func ParseBibTeX(filename string, data []byte) ([]BibEntry, error) {
+	p := bibParser{file: filename, data: string(data), lines: newLineIndex(data), macros: make(map[string]string)}
+	for name, month := range bibMonths {
+		p.macros[name] = month
+	}
+
+	var entries []BibEntry
+	for {
+		at := strings.IndexByte(p.data[p.pos:], '@')
+		if at < 0 {
+			return entries, nil
+		}
+		p.pos += at
+		start := p.pos
+		p.pos++
+
+		kind := strings.ToLower(p.identifier())
+		p.skipSpace()
+		if p.pos >= len(p.data) || (p.data[p.pos] != '{' && p.data[p.pos] != '(') {
+			return nil, p.problem(start, "expected { after @%s", kind)
+		}
+		closing := byte('}')
+		if p.data[p.pos] == '(' {
+			closing = ')'
+		}
+		p.pos++
+
+		switch kind {
+		case "comment", "preamble":
+			if err := p.skipBlock(start, closing); err != nil {
+				return nil, err
+			}
+		case "string":
+			fields, err := p.fields(closing)
+			if err != nil {
+				return nil, err
+			}
+			for name, value := range fields {
+				p.macros[name] = value
+			}
+		default:
+			p.skipSpace()
+			key := strings.TrimSpace(p.until("," + string(closing)))
+			if p.pos < len(p.data) && p.data[p.pos] == ',' {
+				p.pos++
+			}
+			fields, err := p.fields(closing)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, BibEntry{Type: kind, Key: key, Fields: fields, Line: p.lines.position(start).Line})
+		}
+	}
+}
+
+type bibParser struct {
+	file   string
+	data   string
+	pos    int
+	lines  lineIndex
+	macros map[string]string
+}
+
+func (p *bibParser) problem(offset int, format string, args ...interface{}) error {
+	pos := p.lines.position(offset)
+	return Problem{Severity: SeverityError, Code: "syntax-error", File: p.file, Line: pos.Line, Column: pos.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *bibParser) skipSpace() {
+	for p.pos < len(p.data) && unicode.IsSpace(rune(p.data[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *bibParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if unicode.IsSpace(rune(c)) || strings.IndexByte("{}()=,#\"", c) >= 0 {
+			break
+		}
+		p.pos++
+	}
+	return p.data[start:p.pos]
+}
+
+// until advances to the first of the stop characters and returns the text before it
+func (p *bibParser) until(stops string) string {
+	start := p.pos
+	for p.pos < len(p.data) && strings.IndexByte(stops, p.data[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.data[start:p.pos]
+}
+
+func (p *bibParser) skipBlock(start int, closing byte) error {
+	depth := 0
+	for ; p.pos < len(p.data); p.pos++ {
+		switch c := p.data[p.pos]; {
+		case c == '{':
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == closing && depth == 0:
+			p.pos++
+			return nil
+		}
+	}
+	return p.problem(start, "unterminated block")
+}
+
+// fields reads "name = value" pairs up to the closing delimiter
+func (p *bibParser) fields(closing byte) (map[string]string, error) {
+	fields := make(map[string]string)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, p.problem(len(p.data), "unexpected end of file")
+		}
+		if p.data[p.pos] == closing {
+			p.pos++
+			return fields, nil
+		}
+
+		nameAt := p.pos
+		name := strings.ToLower(p.identifier())
+		if name == "" {
+			return nil, p.problem(nameAt, "expected a field name, found %q", p.data[p.pos])
+		}
+		p.skipSpace()
+		if p.pos >= len(p.data) || p.data[p.pos] != '=' {
+			return nil, p.problem(nameAt, "expected = after field %s", name)
+		}
+		p.pos++
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = value
+
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == ',' {
+			p.pos++
+		}
+	}
+}
+
+// value reads a field value: braced or quoted text, a number or a macro,
+// joined with #
+func (p *bibParser) value() (string, error) {
+	var value strings.Builder
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return "", p.problem(len(p.data), "unexpected end of file")
+		}
+		start := p.pos
+		switch p.data[p.pos] {
+		case '{', '"':
+			delimiter := p.data[p.pos]
+			p.pos++
+			depth := 0
+			for {
+				if p.pos >= len(p.data) {
+					return "", p.problem(start, "unterminated value")
+				}
+				c := p.data[p.pos]
+				if c == '{' {
+					depth++
+				} else if c == '}' && depth > 0 {
+					depth--
+				} else if depth == 0 && ((delimiter == '{' && c == '}') || (delimiter == '"' && c == '"')) {
+					break
+				}
+				p.pos++
+			}
+			value.WriteString(p.data[start+1 : p.pos])
+			p.pos++
+		default:
+			word := p.identifier()
+			if word == "" {
+				return "", p.problem(start, "expected a value")
+			}
+			if macro, ok := p.macros[strings.ToLower(word)]; ok {
+				word = macro
+			}
+			value.WriteString(word)
+		}
+
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == '#' {
+			p.pos++
+			continue
+		}
+		return value.String(), nil
+	}
+}
+
+// latexAccents maps an accent command to the letters it combines with and
+// the accented letters they become
+var latexAccents = map[byte][2]string{
+	'`':  {"aeiouAEIOU", "àèìòùÀÈÌÒÙ"},
+	'\'': {"aeiouyAEIOUYcnszCNSZ", "áéíóúýÁÉÍÓÚÝćńśźĆŃŚŹ"},
+	'^':  {"aeiouAEIOU", "âêîôûÂÊÎÔÛ"},
+	'"':  {"aeiouyAEIOU", "äëïöüÿÄËÏÖÜ"},
+	'~':  {"anoANO", "ãñõÃÑÕ"},
+	'c':  {"cCsS", "çÇşŞ"},
+	'v':  {"cszrCSZR", "čšžřČŠŽŘ"},
+}
+
+var (
+	latexAccent = regexp.MustCompile(`\\([` + "`" + `'^"~])\s*\{?\\?([A-Za-z])\}?`)
+	// Letter accents need a brace or space, or \cite would read as \c i
+	latexLetterAccent = regexp.MustCompile(`\\([cv])(?:\{|\s+)\\?([A-Za-z])\}?`)
+	latexSymbol       = regexp.MustCompile(`\\(ss|ae|AE|oe|OE|aa|AA|o|O|l|L)\b\s*`)
+	latexEscape       = regexp.MustCompile(`\\([&%$#_])`)
+	latexCommand      = regexp.MustCompile(`\\[A-Za-z]+\s*`)
+	latexSpaces       = regexp.MustCompile(`\s+`)
+	latexSymbols      = map[string]string{"ss": "ß", "ae": "æ", "AE": "Æ", "oe": "œ", "OE": "Œ", "aa": "å", "AA": "Å", "o": "ø", "O": "Ø", "l": "ł", "L": "Ł"}
+	latexDashes       = strings.NewReplacer("---", "—", "--", "–", "~", " ", "``", "“", "''", "”")
+	latexBraces       = strings.NewReplacer("{", "", "}", "")
+	// Escaped braces hide behind control characters while the rest are removed
+	latexUnescape = strings.NewReplacer("\x01", "{", "\x02", "}")
+)
+
+// LatexText turns a BibTeX value into plain text: accents and symbols
+// become their characters, dashes and ties are typeset, and braces and
+// formatting commands such as \emph are dropped
+func LatexText(value string) string {
+	for _, pattern := range []*regexp.Regexp{latexAccent, latexLetterAccent} {
+		value = pattern.ReplaceAllStringFunc(value, func(match string) string {
+			parts := pattern.FindStringSubmatch(match)
+			accent := latexAccents[parts[1][0]]
+			if i := strings.Index(accent[0], parts[2]); i >= 0 {
+				return string([]rune(accent[1])[i])
+			}
+			return parts[2]
+		})
+	}
+	value = latexSymbol.ReplaceAllStringFunc(value, func(match string) string {
+		return latexSymbols[strings.TrimSpace(match)[1:]]
+	})
+	value = latexEscape.ReplaceAllString(value, "$1")
+	value = strings.NewReplacer(`\{`, "\x01", `\}`, "\x02").Replace(value)
+	value = latexCommand.ReplaceAllString(value, "")
+	value = latexUnescape.Replace(latexBraces.Replace(value))
+	value = latexDashes.Replace(value)
+	return strings.TrimSpace(latexSpaces.ReplaceAllString(value, " "))
+}
@@ -0,0 +1,489 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CitationStyles lists the styles a publications section can use. The
+// first is the default.
+var CitationStyles = []string{"apa", "ieee", "acm"}
+
+func isCitationStyle(style string) bool {
+	for _, known := range CitationStyles {
+		if strings.EqualFold(style, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// TextRun is a stretch of text in one style. Citations are built from runs
+// so renderers can bold the owner's name and link DOIs.
+type TextRun struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Link   string
+}
+
+// Citation is one formatted publication
+type Citation struct {
+	Key  string
+	Year int
+	Runs []TextRun
+}
+
+// Publications is the content of a publications section: the .bib file
+// to read and, optionally, how the owner's name is written in it when it
+// differs from the personal info
+type Publications struct {
+	BibTeX    string
+	Highlight string
+}
+
+var (
+	bibAnd     = regexp.MustCompile(`(?i)\s+and\s+`)
+	bibYear    = regexp.MustCompile(`\d{4}`)
+	doiPrefix  = regexp.MustCompile(`(?i)^(https?://(dx\.)?doi\.org/|doi:\s*)`)
+	thesisKind = map[string][2]string{
+		"phdthesis":     {"Doctoral dissertation", "Ph.D. dissertation"},
+		"mastersthesis": {"Master's thesis", "Master's thesis"},
+	}
+)
+
+// PublicationsData reads a publications section: an object with a bibtex
+// file name and an optional highlight name
+func PublicationsData(data interface{}) (Publications, bool) {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return Publications{}, false
+	}
+	bibtex, ok := object["bibtex"].(string)
+	if !ok {
+		return Publications{}, false
+	}
+	highlight, _ := object["highlight"].(string)
+	return Publications{BibTeX: bibtex, Highlight: highlight}, true
+}
+
+// LoadCitations reads the .bib file of a publications section, relative to
+// the content file, and formats it in style, bolding the owner: the
+// highlight name or the personal name
+func LoadCitations(content *Content, data interface{}, style string) ([]Citation, error) {
+	publications, ok := PublicationsData(data)
+	if !ok {
+		return nil, fmt.Errorf("publications sections need a bibtex file")
+	}
+	entries, err := LoadBibTeX(content.ResolvePath(publications.BibTeX))
+	if err != nil {
+		return nil, err
+	}
+	return FormatCitations(entries, style, firstNonEmpty(publications.Highlight, content.Personal.Name)), nil
+}
+
+// BibliographyFiles lists the .bib files the publications sections of the
+// content read, resolved against the content file
+func BibliographyFiles(content *Content) []string {
+	var files []string
+	for _, key := range SortedKeys(content.Sections) {
+		if publications, ok := PublicationsData(content.Sections[key]); ok {
+			files = append(files, content.ResolvePath(publications.BibTeX))
+		}
+	}
+	return files
+}
+
+// FormatCitations formats entries newest first in one of the
+// CitationStyles, bolding the author named owner. IEEE entries are
+// numbered in that order.
+func FormatCitations(entries []BibEntry, style, owner string) []Citation {
+	style = strings.ToLower(firstNonEmpty(style, CitationStyles[0]))
+	ownerName := parseBibName(owner)
+
+	citations := make([]Citation, len(entries))
+	for i, entry := range entries {
+		citations[i] = Citation{Key: entry.Key, Year: bibEntryYear(entry)}
+	}
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	// Newest first; entries without a year go last
+	sort.SliceStable(order, func(a, b int) bool {
+		ya, yb := citations[order[a]].Year, citations[order[b]].Year
+		if ya == 0 || yb == 0 {
+			return ya != 0 && yb == 0
+		}
+		return ya > yb
+	})
+
+	sorted := make([]Citation, len(entries))
+	for n, i := range order {
+		c := citationBuilder{entry: entries[i], owner: ownerName}
+		switch style {
+		case "ieee":
+			c.text(fmt.Sprintf("[%d] ", n+1))
+			c.ieee()
+		case "acm":
+			c.acm()
+		default:
+			c.apa()
+		}
+		sorted[n] = citations[i]
+		sorted[n].Runs = c.runs
+	}
+	return sorted
+}
+
+func bibEntryYear(entry BibEntry) int {
+	year, _ := strconv.Atoi(bibYear.FindString(entry.Fields["year"]))
+	return year
+}
+
+type bibName struct {
+	given, family string
+}
+
+// splitBibNames splits an author list on the "and"s outside braces
+func splitBibNames(value string) []bibName {
+	var names []bibName
+	depth, start := 0, 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth != 0 {
+			continue
+		}
+		if loc := bibAnd.FindStringIndex(value[i:]); loc != nil && loc[0] == 0 {
+			names = append(names, parseBibName(value[start:i]))
+			i += loc[1] - 1
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(value[start:]); last != "" {
+		names = append(names, parseBibName(last))
+	}
+	return names
+}
+
+// parseBibName reads "Family, Given", "Family, Jr, Given" or "Given von
+// Family". A fully braced name, such as an organisation, is all family.
+func parseBibName(name string) bibName {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, "{") && strings.HasSuffix(name, "}") && strings.Count(name, "{") == 1 {
+		return bibName{family: LatexText(name)}
+	}
+
+	if parts := strings.Split(name, ","); len(parts) > 1 {
+		return bibName{given: LatexText(parts[len(parts)-1]), family: LatexText(parts[0])}
+	}
+
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return bibName{}
+	}
+	// The family name starts at the first lower-case "von" word, if any
+	cut := len(words) - 1
+	for i, word := range words[:len(words)-1] {
+		if r := []rune(LatexText(word)); len(r) > 0 && unicode.IsLower(r[0]) {
+			cut = i
+			break
+		}
+	}
+	return bibName{given: LatexText(strings.Join(words[:cut], " ")), family: LatexText(strings.Join(words[cut:], " "))}
+}
+
+// initials abbreviates given names, as in "Jean-Paul Marie" to "J.-P. M."
+func (n bibName) initials() string {
+	var parts []string
+	for _, word := range strings.Fields(n.given) {
+		var pieces []string
+		for _, piece := range strings.Split(word, "-") {
+			if r := []rune(piece); len(r) > 0 {
+				pieces = append(pieces, string(r[0])+".")
+			}
+		}
+		parts = append(parts, strings.Join(pieces, "-"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// matches reports whether n names the same person as owner: the same
+// family name and, when both have one, the same first initial
+func (n bibName) matches(owner bibName) bool {
+	if owner.family == "" || !strings.EqualFold(n.family, owner.family) {
+		return false
+	}
+	if n.given == "" || owner.given == "" {
+		return true
+	}
+	return strings.EqualFold(string([]rune(n.given)[:1]), string([]rune(owner.given)[:1]))
+}
+
+// citationBuilder collects the runs of one citation
+type citationBuilder struct {
+	entry BibEntry
+	owner bibName
+	runs  []TextRun
+}
+
+func (c *citationBuilder) field(name string) string {
+	return LatexText(c.entry.Fields[name])
+}
+
+func (c *citationBuilder) add(run TextRun) {
+	if run.Text == "" {
+		return
+	}
+	if last := len(c.runs) - 1; last >= 0 {
+		prev := &c.runs[last]
+		if prev.Bold == run.Bold && prev.Italic == run.Italic && prev.Link == run.Link {
+			prev.Text += run.Text
+			return
+		}
+	}
+	c.runs = append(c.runs, run)
+}
+
+func (c *citationBuilder) text(text string)   { c.add(TextRun{Text: text}) }
+func (c *citationBuilder) italic(text string) { c.add(TextRun{Text: text, Italic: true}) }
+
+// authors writes the author list, or the editors when there are no
+// authors, with the owner in bold. It reports whether there was anyone to
+// list.
+func (c *citationBuilder) authors(format func(bibName) string, pairSep, lastSep string) bool {
+	names := splitBibNames(c.entry.Fields["author"])
+	if len(names) == 0 {
+		names = splitBibNames(c.entry.Fields["editor"])
+	}
+	for i, name := range names {
+		switch {
+		case i == 0:
+		case len(names) == 2:
+			c.text(pairSep)
+		case i == len(names)-1:
+			c.text(lastSep)
+		default:
+			c.text(", ")
+		}
+		c.add(TextRun{Text: format(name), Bold: name.matches(c.owner)})
+	}
+	return len(names) > 0
+}
+
+// link writes the DOI as a link, or the URL when there is no DOI
+func (c *citationBuilder) link(doiLabel string) {
+	if doi := doiPrefix.ReplaceAllString(strings.TrimSpace(c.entry.Fields["doi"]), ""); doi != "" {
+		url := "https://doi.org/" + doi
+		if doiLabel != "" {
+			c.text(doiLabel)
+			c.add(TextRun{Text: doi, Link: url})
+		} else {
+			c.add(TextRun{Text: url, Link: url})
+		}
+		return
+	}
+	if url := strings.TrimSpace(c.entry.Fields["url"]); url != "" {
+		c.add(TextRun{Text: url, Link: url})
+	}
+}
+
+func (c *citationBuilder) hasLink() bool {
+	return c.entry.Fields["doi"] != "" || c.entry.Fields["url"] != ""
+}
+
+// sentence ends text with a full stop unless it already has punctuation
+func sentence(text string) string {
+	if text == "" || strings.ContainsAny(text[len(text)-1:], ".?!") {
+		return text
+	}
+	return text + "."
+}
+
+// venue is where an entry appeared besides a journal or proceedings
+func (c *citationBuilder) venue() string {
+	return firstNonEmpty(c.field("publisher"), c.field("howpublished"), c.field("institution"), c.field("organization"), c.field("school"))
+}
+
+// apa writes "Family, G., & Other, A. (2020). Title. Journal, 12(3), 45–67. https://doi.org/..."
+func (c *citationBuilder) apa() {
+	c.authors(func(n bibName) string {
+		// Group authors without initials still end with a full stop
+		return sentence(strings.TrimSuffix(n.family+", "+n.initials(), ", "))
+	}, ", & ", ", & ")
+	year := firstNonEmpty(c.field("year"), "n.d.")
+	c.text(fmt.Sprintf(" (%s). ", year))
+
+	title := c.field("title")
+	switch c.entry.Type {
+	case "article":
+		c.text(sentence(title) + " ")
+		c.italic(c.field("journal"))
+		if volume := c.field("volume"); volume != "" {
+			c.text(", ")
+			c.italic(volume)
+		}
+		if number := c.field("number"); number != "" {
+			c.text("(" + number + ")")
+		}
+		if pages := c.field("pages"); pages != "" {
+			c.text(", " + pages)
+		}
+		c.text(".")
+	case "inproceedings", "conference", "incollection":
+		c.text(sentence(title) + " In ")
+		c.italic(c.field("booktitle"))
+		if pages := c.field("pages"); pages != "" {
+			c.text(" (pp. " + pages + ")")
+		}
+		c.text(".")
+		if publisher := c.field("publisher"); publisher != "" {
+			c.text(" " + sentence(publisher))
+		}
+	case "book":
+		c.italic(sentence(title))
+		if publisher := c.field("publisher"); publisher != "" {
+			c.text(" " + sentence(publisher))
+		}
+	case "phdthesis", "mastersthesis":
+		c.italic(title)
+		c.text(fmt.Sprintf(" [%s, %s].", thesisKind[c.entry.Type][0], c.field("school")))
+	default:
+		c.text(sentence(title))
+		if venue := c.venue(); venue != "" {
+			c.text(" " + sentence(venue))
+		}
+	}
+
+	if c.hasLink() {
+		c.text(" ")
+		c.link("")
+	}
+}
+
+// ieee writes "G. Family, A. Other, and B. Third, “Title,” Journal, vol. 12, no. 3, pp. 45–67, 2020, doi: ..."
+func (c *citationBuilder) ieee() {
+	c.authors(func(n bibName) string {
+		return strings.TrimSpace(n.initials() + " " + n.family)
+	}, " and ", ", and ")
+
+	var parts []string
+	title := c.field("title")
+	// The comma after a quoted title sits inside the quotes
+	sep := ", "
+	switch c.entry.Type {
+	case "book":
+		c.text(", ")
+		c.italic(title)
+		parts = append(parts, c.field("publisher"))
+	case "article":
+		c.text(", “" + title + ",” ")
+		c.italic(c.field("journal"))
+		if volume := c.field("volume"); volume != "" {
+			parts = append(parts, "vol. "+volume)
+		}
+		if number := c.field("number"); number != "" {
+			parts = append(parts, "no. "+number)
+		}
+		if pages := c.field("pages"); pages != "" {
+			parts = append(parts, "pp. "+pages)
+		}
+	case "inproceedings", "conference", "incollection":
+		c.text(", “" + title + ",” in ")
+		c.italic(c.field("booktitle"))
+		if pages := c.field("pages"); pages != "" {
+			parts = append(parts, "pp. "+pages)
+		}
+	case "phdthesis", "mastersthesis":
+		c.text(", “" + title + ",” " + thesisKind[c.entry.Type][1])
+		parts = append(parts, c.field("school"))
+	default:
+		c.text(", “" + title + ",”")
+		sep = " "
+		parts = append(parts, c.venue())
+	}
+	parts = append(parts, c.field("year"))
+
+	for _, part := range parts {
+		if part != "" {
+			c.text(sep + part)
+			sep = ", "
+		}
+	}
+	if c.hasLink() {
+		c.text(", ")
+		c.link("doi: ")
+	}
+	c.text(".")
+}
+
+// acm writes "Given Family and Other Author. 2020. Title. Journal 12, 3 (2020), 45–67. https://doi.org/..."
+func (c *citationBuilder) acm() {
+	hasAuthors := c.authors(func(n bibName) string {
+		return strings.TrimSpace(n.given + " " + n.family)
+	}, " and ", ", and ")
+	if hasAuthors {
+		c.text(". ")
+	}
+	year := c.field("year")
+	if year != "" {
+		c.text(sentence(year) + " ")
+	}
+
+	title := c.field("title")
+	switch c.entry.Type {
+	case "article":
+		c.text(sentence(title) + " ")
+		c.italic(c.field("journal"))
+		if volume := c.field("volume"); volume != "" {
+			c.text(" " + volume)
+		}
+		if number := c.field("number"); number != "" {
+			c.text(", " + number)
+		}
+		if year != "" {
+			c.text(" (" + year + ")")
+		}
+		if pages := c.field("pages"); pages != "" {
+			c.text(", " + pages)
+		}
+		c.text(".")
+	case "inproceedings", "conference", "incollection":
+		c.text(sentence(title) + " In ")
+		c.italic(c.field("booktitle"))
+		if publisher := c.field("publisher"); publisher != "" {
+			c.text(". " + publisher)
+		}
+		if pages := c.field("pages"); pages != "" {
+			c.text(", " + pages)
+		}
+		c.text(".")
+	case "book":
+		c.italic(sentence(title))
+		if publisher := c.field("publisher"); publisher != "" {
+			c.text(" " + sentence(publisher))
+		}
+	case "phdthesis", "mastersthesis":
+		c.italic(sentence(title))
+		c.text(" " + thesisKind[c.entry.Type][1] + ". " + sentence(c.field("school")))
+	default:
+		c.text(sentence(title))
+		if venue := c.venue(); venue != "" {
+			c.text(" " + sentence(venue))
+		}
+	}
+
+	if c.hasLink() {
+		c.text(" ")
+		c.link("")
+	}
+}
@@ -0,0 +1,49 @@
+package utils
+
+import "testing"
+
+// citationText joins the runs of a citation into plain text
+func citationText(citation Citation) string {
+	var text string
+	for _, run := range citation.Runs {
+		text += run.Text
+	}
+	return text
+}
+
+func TestACMCitations(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   string
+	}{
+		{
+			name:   "authors and year",
+			fields: map[string]string{"author": "Doe, Jane and Roe, Rick", "title": "Fast Builds", "year": "2021", "publisher": "Example Press"},
+			want:   "Jane Doe and Rick Roe. 2021. Fast Builds. Example Press.",
+		},
+		{
+			name:   "no year",
+			fields: map[string]string{"author": "Doe, Jane", "title": "Fast Builds", "publisher": "Example Press"},
+			want:   "Jane Doe. Fast Builds. Example Press.",
+		},
+		{
+			name:   "no authors",
+			fields: map[string]string{"title": "Fast Builds", "year": "2021", "publisher": "Example Press"},
+			want:   "2021. Fast Builds. Example Press.",
+		},
+		{
+			name:   "no authors or year",
+			fields: map[string]string{"title": "Fast Builds", "publisher": "Example Press"},
+			want:   "Fast Builds. Example Press.",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			citations := FormatCitations([]BibEntry{{Type: "misc", Key: "doe", Fields: test.fields}}, "acm", "")
+			if got := citationText(citations[0]); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Severity ranks a Problem. Errors stop a build; warnings don't.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is one error or warning, located in its source file when known.
+// The json tags define the record written by --diagnostics=json.
+type Problem struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Section  string   `json:"section,omitempty"`
+	Icon     string   `json:"icon,omitempty"`
+}
+
+func (p Problem) Error() string {
+	location := p.File
+	if p.Line > 0 {
+		location += fmt.Sprintf(":%d", p.Line)
+		if p.Column > 0 {
+			location += fmt.Sprintf(":%d", p.Column)
+		}
+	}
+
+	parts := []string{}
+	if location != "" {
+		parts = append(parts, location)
+	}
+	if p.Path != "" {
+		parts = append(parts, p.Path)
+	}
+	parts = append(parts, p.Message)
+	return strings.Join(parts, ": ")
+}
+
+// IsWarning reports whether the problem should not stop a build
+func (p Problem) IsWarning() bool {
+	return p.Severity == SeverityWarning
+}
+
+// locatedProblem builds an error positioned at path in src
+func locatedProblem(src *Source, path, message string) Problem {
+	pos := src.Locate(path)
+	return Problem{
+		Severity: SeverityError,
+		File:     sourceFile(src),
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Path:     path,
+		Message:  message,
+	}
+}
+
+func sourceFile(src *Source) string {
+	if src == nil {
+		return ""
+	}
+	return src.File
+}
+
+// Problems collects every problem found by a validation pass
+type Problems []Problem
+
+func (p Problems) Error() string {
+	lines := make([]string, len(p))
+	for i, problem := range p {
+		lines[i] = problem.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// split separates warnings from errors
+func (p Problems) split() (errs, warnings Problems) {
+	for _, problem := range p {
+		if problem.IsWarning() {
+			warnings = append(warnings, problem)
+		} else {
+			errs = append(errs, problem)
+		}
+	}
+	return errs, warnings
+}
+
+// sorted orders problems by position, keeping files in the given order
+func (p Problems) sorted(files ...string) Problems {
+	rank := func(file string) int {
+		for i, name := range files {
+			if name == file {
+				return i
+			}
+		}
+		return len(files)
+	}
+
+	sort.SliceStable(p, func(i, j int) bool {
+		a, b := p[i], p[j]
+		if a.File != b.File {
+			return rank(a.File) < rank(b.File)
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return p
+}
+
+var (
+	warningMu      sync.Mutex
+	warningHandler = func(p Problem) { log.Printf("Warning: %s", p.Error()) }
+)
+
+// SetWarningHandler replaces where warnings raised while loading and
+// rendering go. By default they are logged.
+func SetWarningHandler(handler func(Problem)) {
+	warningMu.Lock()
+	defer warningMu.Unlock()
+	warningHandler = handler
+}
+
+// Warn passes a warning to the current handler
+func Warn(p Problem) {
+	p.Severity = SeverityWarning
+	warningMu.Lock()
+	defer warningMu.Unlock()
+	warningHandler(p)
+}
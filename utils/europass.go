@@ -0,0 +1,472 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// europassBlockTags matches the HTML tags Europass rich text breaks lines at
+var europassBlockTags = regexp.MustCompile(`(?i)<\s*(/?(p|li|ul|ol|div)|br\s*/?)\s*>`)
+
+var europassTags = regexp.MustCompile(`<[^>]*>`)
+
+// europassSkillLevels names the CEFR self-assessment columns of a language
+var europassSkillLevels = []struct{ element, label string }{
+	{"Listening", "listening"},
+	{"Reading", "reading"},
+	{"SpokenInteraction", "spoken interaction"},
+	{"SpokenProduction", "spoken production"},
+	{"Writing", "writing"},
+}
+
+// europassDigitalAreas names the areas of the digital competence self-assessment
+var europassDigitalAreas = []struct{ element, label string }{
+	{"Information", "information processing"},
+	{"Communication", "communication"},
+	{"ContentCreation", "content creation"},
+	{"Safety", "safety"},
+	{"ProblemSolving", "problem solving"},
+}
+
+var europassDigitalLevels = map[string]string{
+	"A": "basic",
+	"B": "independent",
+	"C": "proficient",
+}
+
+// xmlElement is one element of a parsed XML document. The tree is kept
+// generic so the elements an importer never read can be reported.
+type xmlElement struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*xmlElement
+	line     int
+	column   int
+	used     bool
+}
+
+// parseXML reads a whole document into an element tree
+func parseXML(filename string, data []byte) (*xmlElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlElement
+	var stack []*xmlElement
+	for {
+		line, column := decoder.InputPos()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return nil, Problem{Severity: SeverityError, Code: "syntax-error", File: filename, Line: syntaxErr.Line, Message: syntaxErr.Msg}
+			}
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &xmlElement{name: t.Name.Local, attrs: make(map[string]string), line: line, column: column}
+			for _, attr := range t.Attr {
+				element.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+			} else {
+				root = element
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, Problem{Severity: SeverityError, Code: "syntax-error", File: filename, Message: "not an XML document"}
+	}
+	return root, nil
+}
+
+// child marks and returns the element at the end of the path of names
+// below e, or nil when there is none
+func (e *xmlElement) child(names ...string) *xmlElement {
+	current := e
+	for _, name := range names {
+		if current == nil {
+			return nil
+		}
+		var next *xmlElement
+		for _, c := range current.children {
+			if c.name == name {
+				next = c
+				break
+			}
+		}
+		current = next
+		if current != nil {
+			current.used = true
+		}
+	}
+	return current
+}
+
+// all marks and returns every child of e named name
+func (e *xmlElement) all(name string) []*xmlElement {
+	if e == nil {
+		return nil
+	}
+	var matches []*xmlElement
+	for _, c := range e.children {
+		if c.name == name {
+			c.used = true
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// value returns the trimmed text at the end of the path
+func (e *xmlElement) value(names ...string) string {
+	if e == nil {
+		return ""
+	}
+	if c := e.child(names...); c != nil {
+		return strings.TrimSpace(c.text)
+	}
+	return ""
+}
+
+// skip marks e and everything below it as read
+func (e *xmlElement) skip() {
+	if e == nil {
+		return
+	}
+	e.used = true
+	for _, c := range e.children {
+		c.skip()
+	}
+}
+
+// ImportEuropass converts a Europass CV in the SkillsPassport XML schema.
+// Personal info, the headline, work experience, education and training,
+// language skills and digital skills are imported; every other element is reported as a
+// warning so nothing goes missing unnoticed.
+func ImportEuropass(filename string) (*Content, error) {
+	data, err := ReadInput(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	file := DisplayName(filename)
+	root, err := parseXML(file, data)
+	if err != nil {
+		return nil, err
+	}
+	if root.name != "SkillsPassport" {
+		return nil, Problem{Severity: SeverityError, Code: "unsupported-format", File: file, Line: root.line, Column: root.column,
+			Message: fmt.Sprintf("not a Europass CV: the root element is %s, not SkillsPassport", root.name)}
+	}
+
+	content := &Content{Sections: make(map[string]interface{})}
+	root.used = true
+	// Document metadata and print settings aren't resume content
+	root.child("DocumentInfo").skip()
+	root.child("PrintingPreferences").skip()
+
+	learner := root.child("LearnerInfo")
+	europassPersonal(learner.child("Identification"), &content.Personal)
+	content.ContactFields = personalContactFields(content.Personal)
+
+	if summary := europassHeadline(learner.child("Headline")); summary != "" {
+		content.Sections["summary"] = map[string]interface{}{"content": summary}
+	}
+
+	if items := europassWork(learner.child("WorkExperienceList")); len(items) > 0 {
+		content.Sections["experience"] = map[string]interface{}{"items": items}
+	}
+	if items := europassEducation(learner.child("EducationList")); len(items) > 0 {
+		content.Sections["education"] = map[string]interface{}{"items": items}
+	}
+
+	skills := learner.child("Skills")
+	if languages := europassLanguages(skills.child("Linguistic")); len(languages) > 0 {
+		content.Sections["languages"] = languages
+	}
+	if digital := europassDigital(skills.child("Computer")); len(digital) > 0 {
+		content.Sections["skills"] = digital
+	}
+
+	for _, problem := range unreadElements(file, root, "") {
+		Warn(problem)
+	}
+	return content, nil
+}
+
+func europassPersonal(identification *xmlElement, personal *PersonalInfo) {
+	if identification == nil {
+		return
+	}
+	name := identification.child("PersonName")
+	personal.Name = strings.TrimSpace(name.value("FirstName") + " " + name.value("Surname"))
+
+	contact := identification.child("ContactInfo")
+	if contact == nil {
+		return
+	}
+	personal.Address = europassAddress(contact.child("Address", "Contact"), true)
+	personal.Email = contact.value("Email", "Contact")
+
+	if phones := contact.child("TelephoneList").all("Telephone"); len(phones) > 0 {
+		personal.Phone = phones[0].value("Contact")
+		phones[0].child("Use").skip()
+		// Only the first number fits the personal info
+		for _, phone := range phones[1:] {
+			phone.used = false
+		}
+	}
+
+	for _, website := range contact.child("WebsiteList").all("Website") {
+		link := website.value("Contact")
+		website.child("Use").skip()
+		var slot *string
+		switch linkNetwork(link, "") {
+		case "github":
+			slot = &personal.GitHub
+		case "linkedin":
+			slot = &personal.LinkedIn
+		default:
+			slot = &personal.Website
+		}
+		if *slot != "" {
+			// Only one link per kind fits the personal info
+			website.used = false
+			continue
+		}
+		*slot = link
+	}
+}
+
+// europassAddress joins an address into one line. Employers and schools
+// only get their town and country.
+func europassAddress(address *xmlElement, full bool) string {
+	if address == nil {
+		return ""
+	}
+	var parts []string
+	if full {
+		parts = append(parts, address.value("AddressLine"), address.value("AddressLine2"))
+		town := strings.TrimSpace(address.value("PostalCode") + " " + address.value("Municipality"))
+		parts = append(parts, town)
+	} else {
+		parts = append(parts, address.value("Municipality"))
+	}
+	country := address.child("Country")
+	parts = append(parts, firstNonEmpty(country.value("Label"), country.value("Code")))
+
+	var line []string
+	for _, part := range parts {
+		if part != "" {
+			line = append(line, part)
+		}
+	}
+	return strings.Join(line, ", ")
+}
+
+func europassWork(list *xmlElement) []interface{} {
+	var items []interface{}
+	for _, work := range list.all("WorkExperience") {
+		start, end := europassPeriod(work.child("Period"))
+		position := work.child("Position")
+		position.child("Code").skip()
+		employer := work.child("Employer")
+		items = append(items, entryValue(map[string]string{
+			"title":      position.value("Label"),
+			"company":    employer.value("Name"),
+			"location":   europassAddress(employer.child("ContactInfo", "Address", "Contact"), false),
+			"start_date": start,
+			"end_date":   end,
+		}, europassText(work.value("Activities"))))
+	}
+	return items
+}
+
+func europassEducation(list *xmlElement) []interface{} {
+	var items []interface{}
+	for _, education := range list.all("Education") {
+		start, end := europassPeriod(education.child("Period"))
+		organisation := education.child("Organisation")
+		items = append(items, entryValue(map[string]string{
+			"degree":      education.value("Title"),
+			"institution": organisation.value("Name"),
+			"location":    europassAddress(organisation.child("ContactInfo", "Address", "Contact"), false),
+			"start_date":  start,
+			"end_date":    end,
+		}, europassText(education.value("Activities"))))
+	}
+	return items
+}
+
+// europassPeriod reads <From year="2021" month="--06"/>, <To .../> and
+// <Current>true</Current> into display dates
+func europassPeriod(period *xmlElement) (start, end string) {
+	if period == nil {
+		return "", ""
+	}
+	start = europassDate(period.child("From"))
+	end = europassDate(period.child("To"))
+	if period.value("Current") == "true" {
+		end = "Present"
+	}
+	return start, end
+}
+
+func europassDate(date *xmlElement) string {
+	if date == nil {
+		return ""
+	}
+	year := date.attrs["year"]
+	month := strings.TrimLeft(date.attrs["month"], "-")
+	if year == "" || month == "" {
+		return year
+	}
+	return formatResumeDate(year + "-" + month)
+}
+
+// europassHeadline is the summary a headline makes: a personal statement
+// as written, any other kind labelled with its type, as in
+// "Job applied for: Backend Engineer"
+func europassHeadline(headline *xmlElement) string {
+	if headline == nil {
+		return ""
+	}
+	code, label := headline.value("Type", "Code"), headline.value("Type", "Label")
+	text := strings.Join(europassText(headline.value("Description", "Label")), " ")
+	if text == "" || code == "personal_statement" || label == "" {
+		return text
+	}
+	return label + ": " + text
+}
+
+// europassText turns the HTML Europass stores in text fields into lines
+func europassText(text string) []string {
+	text = europassBlockTags.ReplaceAllString(text, "\n")
+	text = europassTags.ReplaceAllString(text, "")
+	return descriptionLines(html.UnescapeString(text))
+}
+
+// europassLanguages lists mother tongues as "German (native)" and foreign
+// languages with their CEFR levels, as in "English (C1)" when every level
+// matches and "English (listening C1, reading C2, ...)" otherwise
+func europassLanguages(linguistic *xmlElement) []interface{} {
+	var languages []interface{}
+	for _, tongue := range linguistic.child("MotherTongueList").all("MotherTongue") {
+		description := tongue.child("Description")
+		description.child("Code").skip()
+		if label := description.value("Label"); label != "" {
+			languages = append(languages, label+" (native)")
+		}
+	}
+
+	for _, language := range linguistic.child("ForeignLanguageList").all("ForeignLanguage") {
+		description := language.child("Description")
+		description.child("Code").skip()
+		label := firstNonEmpty(description.value("Label"), description.value("Code"))
+		if label == "" {
+			continue
+		}
+
+		proficiency := language.child("ProficiencyLevel")
+		var levels []string
+		same := true
+		first := ""
+		for _, skill := range europassSkillLevels {
+			level := proficiency.value(skill.element)
+			if level == "" {
+				continue
+			}
+			if first == "" {
+				first = level
+			}
+			same = same && level == first
+			levels = append(levels, skill.label+" "+level)
+		}
+
+		switch {
+		case len(levels) == 0:
+			languages = append(languages, label)
+		case same && len(levels) == len(europassSkillLevels):
+			languages = append(languages, fmt.Sprintf("%s (%s)", label, first))
+		default:
+			languages = append(languages, fmt.Sprintf("%s (%s)", label, strings.Join(levels, ", ")))
+		}
+	}
+	return languages
+}
+
+// europassDigital lists the digital skills description line by line,
+// followed by the self-assessment when there is one
+func europassDigital(computer *xmlElement) []interface{} {
+	if computer == nil {
+		return nil
+	}
+	var skills []interface{}
+	for _, line := range europassText(computer.value("Description")) {
+		skills = append(skills, line)
+	}
+
+	proficiency := computer.child("ProficiencyLevel")
+	var areas []string
+	for _, area := range europassDigitalAreas {
+		if level := europassDigitalLevels[proficiency.value(area.element)]; level != "" {
+			areas = append(areas, fmt.Sprintf("%s (%s)", area.label, level))
+		}
+	}
+	if len(areas) > 0 {
+		skills = append(skills, "Digital competence: "+strings.Join(areas, ", "))
+	}
+	return skills
+}
+
+// unreadElements reports the outermost elements the import never read.
+// Paths leave out the root and index elements that repeat, as in
+// "LearnerInfo.WorkExperienceList.WorkExperience[1].Employer.Sector".
+func unreadElements(file string, e *xmlElement, path string) Problems {
+	if !e.used {
+		return Problems{{
+			Severity: SeverityWarning,
+			Code:     "not-imported",
+			File:     file,
+			Line:     e.line,
+			Column:   e.column,
+			Path:     path,
+			Message:  e.name + " has no place in the content and was not imported",
+		}}
+	}
+
+	counts := make(map[string]int)
+	for _, c := range e.children {
+		counts[c.name]++
+	}
+	seen := make(map[string]int)
+	var problems Problems
+	for _, c := range e.children {
+		childPath := joinPath(path, c.name)
+		if counts[c.name] > 1 {
+			childPath = indexPath(childPath, seen[c.name])
+		}
+		seen[c.name]++
+		problems = append(problems, unreadElements(file, c, childPath)...)
+	}
+	return problems
+}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// importEuropass imports a Europass file and collects the warnings it raises
+func importEuropass(t *testing.T, filename string) (*Content, Problems) {
+	t.Helper()
+	var warnings Problems
+	previous := warningHandler
+	SetWarningHandler(func(p Problem) { warnings = append(warnings, p) })
+	t.Cleanup(func() { SetWarningHandler(previous) })
+
+	content, err := ImportEuropass(filename)
+	if err != nil {
+		t.Fatalf("ImportEuropass(%s): %v", filename, err)
+	}
+	return content, warnings
+}
+
+func TestImportEuropassExample(t *testing.T) {
+	content, warnings := importEuropass(t, "../example-europass.xml")
+
+	wantPersonal := PersonalInfo{
+		Name:    "Anna Schmidt",
+		Email:   "anna.schmidt@example.com",
+		Phone:   "+49 30 1234567",
+		Address: "Invalidenstraße 42, 10115 Berlin, Germany",
+		Website: "https://example.com",
+		GitHub:  "https://github.com/annaschmidt",
+	}
+	if content.Personal != wantPersonal {
+		t.Errorf("personal info:\n got %+v\nwant %+v", content.Personal, wantPersonal)
+	}
+
+	var fields []string
+	for _, field := range content.ContactFields {
+		fields = append(fields, field.Field)
+	}
+	if want := []string{"address", "email", "phone", "website", "github"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("contact fields = %v, want %v", fields, want)
+	}
+
+	wantSections := map[string]interface{}{
+		"summary": map[string]interface{}{"content": "Job applied for: Backend Engineer"},
+		"experience": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{
+				"title":      "Senior Software Engineer",
+				"company":    "Example GmbH",
+				"location":   "Berlin, Germany",
+				"start_date": "Mar 2021",
+				"end_date":   "Present",
+				"description": []interface{}{
+					"Designed the event pipeline that replaced nightly batch imports",
+					"Mentored four engineers through their first production launches",
+				},
+			},
+			map[string]interface{}{
+				"title":      "Software Engineer",
+				"company":    "Sample Systems B.V.",
+				"location":   "Amsterdam, Netherlands",
+				"start_date": "Jul 2018",
+				"end_date":   "Feb 2021",
+				"description": []interface{}{
+					"Built internal APIs in Go serving 2k requests per second",
+					"Cut CI time in half by parallelizing the integration suite",
+				},
+			},
+		}},
+		"education": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{
+				"degree":      "B.Sc. in Computer Science",
+				"institution": "Technische Universität Berlin",
+				"location":    "Berlin, Germany",
+				"start_date":  "Oct 2014",
+				"end_date":    "Jun 2018",
+				"description": []interface{}{"Thesis on consensus protocols for edge networks"},
+			},
+		}},
+		"languages": []interface{}{
+			"German (native)",
+			"English (C1)",
+			"French (listening B2, reading B2, spoken interaction B1, spoken production B1, writing A2)",
+		},
+		"skills": []interface{}{
+			"Go, PostgreSQL, Kubernetes",
+			"Terraform and gRPC",
+			"Digital competence: information processing (proficient), communication (proficient), content creation (proficient), safety (independent), problem solving (proficient)",
+		},
+	}
+	for key, want := range wantSections {
+		if got := content.Sections[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("sections.%s:\n got %#v\nwant %#v", key, got, want)
+		}
+	}
+	if len(content.Sections) != len(wantSections) {
+		t.Errorf("imported sections %v, want only %v", SortedKeys(content.Sections), SortedKeys(wantSections))
+	}
+
+	wantWarnings := []struct {
+		line int
+		path string
+	}{
+		{62, "LearnerInfo.Identification.Demographics"},
+		{101, "LearnerInfo.WorkExperienceList.WorkExperience[0].Employer.Sector"},
+		{154, "LearnerInfo.EducationList.Education.Level"},
+		{199, "LearnerInfo.Skills.Communication"},
+		{212, "LearnerInfo.Skills.Driving"},
+	}
+	if len(warnings) != len(wantWarnings) {
+		t.Fatalf("got %d warnings, want %d: %v", len(warnings), len(wantWarnings), warnings)
+	}
+	for i, want := range wantWarnings {
+		got := warnings[i]
+		if got.Code != "not-imported" || got.Severity != SeverityWarning || got.Line != want.line || got.Path != want.path {
+			t.Errorf("warning %d = %s %s line %d %s, want not-imported warning line %d %s",
+				i, got.Severity, got.Code, got.Line, got.Path, want.line, want.path)
+		}
+	}
+}
+
+func TestImportEuropassPersonalStatement(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cv.xml")
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<SkillsPassport>
+  <LearnerInfo>
+    <Identification>
+      <PersonName><FirstName>Anna</FirstName><Surname>Schmidt</Surname></PersonName>
+    </Identification>
+    <Headline>
+      <Type><Code>personal_statement</Code><Label>Personal statement</Label></Type>
+      <Description><Label>&lt;p&gt;Backend engineer who likes boring systems.&lt;/p&gt;</Label></Description>
+    </Headline>
+  </LearnerInfo>
+</SkillsPassport>`
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, warnings := importEuropass(t, filename)
+	want := map[string]interface{}{"content": "Backend engineer who likes boring systems."}
+	if got := content.Sections["summary"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %#v, want %#v", got, want)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestImportEuropassRejectsOtherXML(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "other.xml")
+	if err := os.WriteFile(filename, []byte("<resume/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ImportEuropass(filename)
+	problem, ok := err.(Problem)
+	if !ok || problem.Code != "unsupported-format" {
+		t.Fatalf("ImportEuropass(other.xml) error = %v, want an unsupported-format problem", err)
+	}
+}
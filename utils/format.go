@@ -0,0 +1,449 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the encoding of a config or content file
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+	// FormatMarkdown is only read as content, never as config
+	FormatMarkdown Format = "markdown"
+)
+
+// DetectFormat picks the file format from the extension, falling back to
+// sniffing the content when the extension is missing or unknown
+func DetectFormat(filename string, data []byte) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".md", ".markdown":
+		return FormatMarkdown
+	}
+
+	// TOML goes first: a table header starts with [ like a JSON array
+	if looksLikeTOML(data) {
+		return FormatTOML
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return FormatJSON
+	}
+	if looksLikeMarkdown(trimmed) {
+		return FormatMarkdown
+	}
+	return FormatYAML
+}
+
+var (
+	markdownTitlePattern   = regexp.MustCompile(`^#{1,6} \S`)
+	markdownSectionPattern = regexp.MustCompile(`(?m)^#{2,6} \S`)
+)
+
+// looksLikeMarkdown spots a Markdown resume: a leading ATX heading with
+// section headings after it. YAML files often open with # comments too, so
+// text that parses as a YAML mapping is left to YAML.
+func looksLikeMarkdown(data []byte) bool {
+	first, rest, _ := strings.Cut(string(data), "\n")
+	if !markdownTitlePattern.MatchString(first) || !markdownSectionPattern.MatchString(rest) {
+		return false
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err == nil {
+		_, mapping := value.(map[string]interface{})
+		return !mapping
+	}
+	return true
+}
+
+// Position is a 1-based line and column in a source file
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Source is a parsed config or content file. It keeps the decoded value
+// tree and the position of every value, keyed by path such as
+// "sections.experience.template" or "contact_fields[2].icon".
+type Source struct {
+	File      string
+	Format    Format
+	Positions map[string]Position
+	Value     interface{}
+}
+
+// Locate returns the position of path, falling back to the closest
+// parent that exists in the file
+func (s *Source) Locate(path string) Position {
+	if s == nil {
+		return Position{}
+	}
+	for {
+		if pos, ok := s.Positions[path]; ok {
+			return pos
+		}
+		if path == "" {
+			return Position{}
+		}
+		path = parentPath(path)
+	}
+}
+
+// Set replaces the value at a dotted path, creating objects on the way.
+// Paths address object keys only, e.g. "sections.skills.enabled".
+func (s *Source) Set(path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	if s.Value == nil {
+		s.Value = make(map[string]interface{})
+	}
+
+	node, ok := s.Value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s is not an object", s.File)
+	}
+	for i, key := range keys[:len(keys)-1] {
+		child, exists := node[key]
+		if !exists || child == nil {
+			child = make(map[string]interface{})
+			node[key] = child
+		}
+		next, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not an object", strings.Join(keys[:i+1], "."))
+		}
+		node = next
+	}
+
+	node[keys[len(keys)-1]] = value
+	return nil
+}
+
+// ParseSource parses data as JSON or YAML and records value positions
+func ParseSource(filename string, data []byte) (*Source, error) {
+	src := &Source{
+		File:      filename,
+		Format:    DetectFormat(filename, data),
+		Positions: make(map[string]Position),
+	}
+
+	switch src.Format {
+	case FormatYAML:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, yamlProblem(filename, err)
+		}
+		value, err := yamlNodeValue(&doc, "", src.Positions)
+		if err != nil {
+			return nil, err
+		}
+		src.Value = value
+	case FormatTOML:
+		value, err := parseTOML(filename, data, src.Positions)
+		if err != nil {
+			return nil, err
+		}
+		src.Value = value
+	case FormatMarkdown:
+		return nil, Problem{Severity: SeverityError, Code: "unsupported-format", File: filename, Message: "Markdown can only be used for resume content"}
+	default:
+		if err := json.Unmarshal(data, &src.Value); err != nil {
+			return nil, jsonProblem(filename, data, err)
+		}
+		scanner := jsonPositionScanner{data: data, lines: newLineIndex(data), positions: src.Positions}
+		scanner.value("")
+	}
+
+	return src, nil
+}
+
+// Decode stores the parsed value in v, reporting type mismatches at the
+// position of the offending value
+func (s *Source) Decode(v interface{}) error {
+	data, err := json.Marshal(s.Value)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		pos := s.Locate(typeErr.Field)
+		return Problem{
+			Severity: SeverityError,
+			Code:     "type-mismatch",
+			File:     s.File,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Path:     typeErr.Field,
+			Message:  fmt.Sprintf("expected %s, found %s", describeType(typeErr.Type), typeErr.Value),
+		}
+	}
+	return err
+}
+
+// setValue stores v as the source's value tree, in the shape Decode expects
+func (s *Source) setValue(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.File, err)
+	}
+	return json.Unmarshal(data, &s.Value)
+}
+
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Ptr:
+		return describeType(t.Elem())
+	default:
+		return t.String()
+	}
+}
+
+// decodeFile decodes data into v according to the detected format
+func decodeFile(filename string, data []byte, v interface{}) (*Source, error) {
+	src, err := ParseSource(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	return src, src.Decode(v)
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func indexPath(parent string, index int) string {
+	return fmt.Sprintf("%s[%d]", parent, index)
+}
+
+func parentPath(path string) string {
+	cut := strings.LastIndexAny(path, ".[")
+	if cut < 0 {
+		return ""
+	}
+	return path[:cut]
+}
+
+// yamlNodeValue converts a YAML node into the same shapes encoding/json
+// produces, keeping dates and other untagged scalars as plain strings
+func yamlNodeValue(node *yaml.Node, path string, positions map[string]Position) (interface{}, error) {
+	if node.Kind != 0 && node.Kind != yaml.DocumentNode {
+		positions[path] = Position{Line: node.Line, Column: node.Column}
+	}
+
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(node.Content[0], path, positions)
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias, path, positions)
+	case yaml.MappingNode:
+		result := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			if keyNode.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: mapping keys must be scalars", keyNode.Line)
+			}
+			// Merge keys (<<: *anchor) pull in the aliased mapping
+			if keyNode.ShortTag() == "!!merge" {
+				merged, err := yamlNodeValue(valueNode, path, positions)
+				if err != nil {
+					return nil, err
+				}
+				if mergedMap, ok := merged.(map[string]interface{}); ok {
+					for k, v := range mergedMap {
+						if _, exists := result[k]; !exists {
+							result[k] = v
+						}
+					}
+				}
+				continue
+			}
+			value, err := yamlNodeValue(valueNode, joinPath(path, keyNode.Value), positions)
+			if err != nil {
+				return nil, err
+			}
+			result[keyNode.Value] = value
+		}
+		return result, nil
+	case yaml.SequenceNode:
+		result := make([]interface{}, 0, len(node.Content))
+		for i, item := range node.Content {
+			value, err := yamlNodeValue(item, indexPath(path, i), positions)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	default:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool", "!!int", "!!float":
+			var value interface{}
+			if err := node.Decode(&value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			return value, nil
+		default:
+			return node.Value, nil
+		}
+	}
+}
+
+var yamlLinePattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+func yamlProblem(filename string, err error) error {
+	match := yamlLinePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	line, _ := strconv.Atoi(match[1])
+	return Problem{Severity: SeverityError, Code: "syntax-error", File: filename, Line: line, Message: match[2]}
+}
+
+func jsonProblem(filename string, data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		pos := newLineIndex(data).position(int(syntaxErr.Offset))
+		return Problem{Severity: SeverityError, Code: "syntax-error", File: filename, Line: pos.Line, Column: pos.Column, Message: syntaxErr.Error()}
+	}
+	return fmt.Errorf("%s: %w", filename, err)
+}
+
+// lineIndex converts byte offsets into line and column numbers
+type lineIndex []int
+
+func newLineIndex(data []byte) lineIndex {
+	starts := lineIndex{0}
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+func (l lineIndex) position(offset int) Position {
+	line := sort.Search(len(l), func(i int) bool { return l[i] > offset }) - 1
+	if line < 0 {
+		line = 0
+	}
+	return Position{Line: line + 1, Column: offset - l[line] + 1}
+}
+
+// jsonPositionScanner walks already validated JSON and records where
+// each value starts
+type jsonPositionScanner struct {
+	data      []byte
+	pos       int
+	lines     lineIndex
+	positions map[string]Position
+}
+
+func (s *jsonPositionScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *jsonPositionScanner) value(path string) {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return
+	}
+	s.positions[path] = s.lines.position(s.pos)
+
+	switch s.data[s.pos] {
+	case '{':
+		s.pos++
+		for {
+			s.skipSpace()
+			if s.pos >= len(s.data) || s.data[s.pos] == '}' {
+				s.pos++
+				return
+			}
+			key := s.str()
+			s.value(joinPath(path, key))
+		}
+	case '[':
+		s.pos++
+		for i := 0; ; i++ {
+			s.skipSpace()
+			if s.pos >= len(s.data) || s.data[s.pos] == ']' {
+				s.pos++
+				return
+			}
+			s.value(indexPath(path, i))
+		}
+	case '"':
+		s.str()
+	default:
+		for s.pos < len(s.data) && !strings.ContainsRune(" \t\r\n,]}", rune(s.data[s.pos])) {
+			s.pos++
+		}
+	}
+}
+
+func (s *jsonPositionScanner) str() string {
+	start := s.pos
+	s.pos++
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos += 2
+			continue
+		case '"':
+			s.pos++
+			var value string
+			json.Unmarshal(s.data[start:s.pos], &value)
+			return value
+		}
+		s.pos++
+	}
+	return ""
+}
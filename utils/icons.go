@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IconCacheEntry describes one icon mapping and its rendered PNG
+type IconCacheEntry struct {
+	Name   string
+	File   string
+	Path   string
+	Cached bool
+}
+
+// ResolveIconColor returns the hex color icons are rendered with,
+// looking the configured color up in the named colors first
+func ResolveIconColor(iconConfig *IconConfig, colors map[string]string) string {
+	if iconConfig.Color == "" {
+		return ""
+	}
+	if colorValue, exists := colors[iconConfig.Color]; exists {
+		return colorValue
+	}
+	return iconConfig.Color
+}
+
+func iconPNGPath(iconFileName, outputDir string, size int, colorHex string) string {
+	colorSuffix := strings.TrimPrefix(colorHex, "#")
+	if colorSuffix == "" {
+		colorSuffix = "000000" // default to black if no color specified
+	}
+	return filepath.Join(outputDir, fmt.Sprintf("%s_%s_%dpx.png", iconFileName, colorSuffix, size))
+}
+
+// IconSVG returns the SVG markup of a mapped icon, for output formats that
+// draw the vector icon instead of the cached PNG
+func IconSVG(iconName string, iconConfig *IconConfig) (string, error) {
+	iconFileName, exists := iconConfig.Mappings[iconName]
+	if !exists {
+		return "", fmt.Errorf("icon %s is not mapped", iconName)
+	}
+
+	for _, svgPath := range iconConfig.SVGPaths {
+		data, err := os.ReadFile(filepath.Join(svgPath, iconFileName+".svg"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return "", fmt.Errorf("icon %s not found in any configured SVG paths", iconFileName)
+}
+
+// ListIconCache reports every mapped icon and whether its PNG is cached
+func ListIconCache(iconConfig *IconConfig, colors map[string]string) []IconCacheEntry {
+	colorHex := ResolveIconColor(iconConfig, colors)
+
+	var entries []IconCacheEntry
+	for name, iconFileName := range iconConfig.Mappings {
+		pngPath := iconPNGPath(iconFileName, iconConfig.OutputDir, iconConfig.DefaultSize, colorHex)
+		_, err := os.Stat(pngPath)
+		entries = append(entries, IconCacheEntry{
+			Name:   name,
+			File:   iconFileName,
+			Path:   pngPath,
+			Cached: err == nil,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// BuildIconCache renders every mapped icon that is not cached yet.
+// With force set, cached PNGs are rendered again.
+func BuildIconCache(iconConfig *IconConfig, colors map[string]string, force bool) ([]IconCacheEntry, error) {
+	colorHex := ResolveIconColor(iconConfig, colors)
+	entries := ListIconCache(iconConfig, colors)
+
+	iconCacheMu.Lock()
+	defer iconCacheMu.Unlock()
+
+	var errs []error
+	for i, entry := range entries {
+		if entry.Cached && !force {
+			continue
+		}
+		err := convertColoredIcon(entry.File, iconConfig.SVGPaths, iconConfig.OutputDir, iconConfig.DefaultSize, colorHex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name, err))
+			continue
+		}
+		entries[i].Cached = true
+	}
+
+	return entries, errors.Join(errs...)
+}
+
+// CleanIconCache removes the cached PNGs of every mapped icon in any color.
+// Files in the output directory that don't belong to a mapping are left alone.
+func CleanIconCache(iconConfig *IconConfig) ([]string, error) {
+	var removed []string
+	seen := make(map[string]bool)
+
+	for _, iconFileName := range iconConfig.Mappings {
+		if seen[iconFileName] {
+			continue
+		}
+		seen[iconFileName] = true
+
+		matches, err := filepath.Glob(filepath.Join(iconConfig.OutputDir, iconFileName+"_*px.png"))
+		if err != nil {
+			return removed, err
+		}
+		for _, match := range matches {
+			if err := os.Remove(match); err != nil {
+				return removed, err
+			}
+			removed = append(removed, match)
+		}
+	}
+
+	sort.Strings(removed)
+	return removed, nil
+}
@@ -0,0 +1,775 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// JSONResume is a document in the JSON Resume schema (jsonresume.org).
+// Only the fields the importer and exporter map are listed.
+type JSONResume struct {
+	Schema       string                  `json:"$schema,omitempty"`
+	Basics       JSONResumeBasics        `json:"basics"`
+	Work         []JSONResumeWork        `json:"work,omitempty"`
+	Education    []JSONResumeEducation   `json:"education,omitempty"`
+	Skills       []JSONResumeSkill       `json:"skills,omitempty"`
+	Languages    []JSONResumeLanguage    `json:"languages,omitempty"`
+	Certificates []JSONResumeCertificate `json:"certificates,omitempty"`
+	Projects     []JSONResumeProject     `json:"projects,omitempty"`
+	Meta         map[string]interface{}  `json:"meta,omitempty"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name,omitempty"`
+	Label    string              `json:"label,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles,omitempty"`
+}
+
+type JSONResumeLocation struct {
+	Address     string `json:"address,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	Region      string `json:"region,omitempty"`
+}
+
+type JSONResumeProfile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name       string   `json:"name,omitempty"`
+	Position   string   `json:"position,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type JSONResumeEducation struct {
+	Institution string   `json:"institution,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name,omitempty"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type JSONResumeLanguage struct {
+	Language string `json:"language,omitempty"`
+	Fluency  string `json:"fluency,omitempty"`
+}
+
+type JSONResumeCertificate struct {
+	Name   string `json:"name,omitempty"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+type JSONResumeProject struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Entity      string   `json:"entity,omitempty"`
+}
+
+// IsJSONResume reports whether a parsed document looks like JSON Resume
+// rather than this tool's content format
+func IsJSONResume(value interface{}) bool {
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, hasBasics := doc["basics"]
+	_, hasPersonal := doc["personal"]
+	return hasBasics && !hasPersonal
+}
+
+// ImportJSONResume reads a JSON Resume document and converts it to content
+func ImportJSONResume(filename string) (*Content, error) {
+	data, err := ReadInput(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := ParseSource(DisplayName(filename), data)
+	if err != nil {
+		return nil, err
+	}
+	content, _, err := jsonResumeContent(src)
+	return content, err
+}
+
+// jsonResumeContent converts a parsed JSON Resume document. The returned
+// source describes the content, with each value positioned where it came
+// from in the original document so validation can point at it.
+func jsonResumeContent(src *Source) (*Content, *Source, error) {
+	var resume JSONResume
+	if err := src.Decode(&resume); err != nil {
+		return nil, nil, err
+	}
+
+	conv := jsonResumeConverter{src: src, positions: make(map[string]Position)}
+	content := conv.convert(&resume)
+
+	contentSrc := &Source{File: src.File, Format: src.Format, Positions: conv.positions}
+	if err := contentSrc.setValue(content); err != nil {
+		return nil, nil, err
+	}
+	return content, contentSrc, nil
+}
+
+type jsonResumeConverter struct {
+	src       *Source
+	positions map[string]Position
+}
+
+// from records that the content value at path came from the document at docPath
+func (c *jsonResumeConverter) from(path, docPath string) {
+	c.positions[path] = c.src.Locate(docPath)
+}
+
+func (c *jsonResumeConverter) convert(resume *JSONResume) *Content {
+	basics := resume.Basics
+	content := &Content{
+		Personal: PersonalInfo{
+			Name:    basics.Name,
+			Email:   basics.Email,
+			Phone:   basics.Phone,
+			Address: formatLocation(basics.Location),
+			Website: basics.URL,
+		},
+		Sections: make(map[string]interface{}),
+	}
+	c.from("personal", "basics")
+	c.from("personal.name", "basics.name")
+	c.from("personal.email", "basics.email")
+	c.from("personal.phone", "basics.phone")
+	c.from("personal.address", "basics.location")
+	c.from("personal.website", "basics.url")
+
+	for i, profile := range basics.Profiles {
+		switch strings.ToLower(profile.Network) {
+		case "github":
+			content.Personal.GitHub = profile.URL
+			c.from("personal.github", indexPath("basics.profiles", i))
+		case "linkedin":
+			content.Personal.LinkedIn = profile.URL
+			c.from("personal.linkedin", indexPath("basics.profiles", i))
+		}
+	}
+
+	content.ContactFields = c.contactFields(basics)
+
+	if basics.Summary != "" {
+		content.Sections["summary"] = map[string]interface{}{"content": basics.Summary}
+		c.from("sections.summary", "basics.summary")
+	}
+
+	if len(resume.Work) > 0 {
+		items := make([]interface{}, len(resume.Work))
+		for i, work := range resume.Work {
+			description := work.Highlights
+			if work.Summary != "" {
+				description = append([]string{work.Summary}, description...)
+			}
+			items[i] = entryValue(map[string]string{
+				"title":      work.Position,
+				"company":    work.Name,
+				"location":   work.Location,
+				"start_date": formatResumeDate(work.StartDate),
+				"end_date":   formatEndDate(work.StartDate, work.EndDate),
+			}, description)
+			c.fromItem("experience", i, indexPath("work", i))
+		}
+		content.Sections["experience"] = map[string]interface{}{"items": items}
+		c.from("sections.experience", "work")
+	}
+
+	if len(resume.Projects) > 0 {
+		items := make([]interface{}, len(resume.Projects))
+		for i, project := range resume.Projects {
+			description := project.Highlights
+			if project.Description != "" {
+				description = append([]string{project.Description}, description...)
+			}
+			items[i] = entryValue(map[string]string{
+				"title":      project.Name,
+				"company":    project.Entity,
+				"start_date": formatResumeDate(project.StartDate),
+				"end_date":   formatEndDate(project.StartDate, project.EndDate),
+			}, description)
+			c.fromItem("projects", i, indexPath("projects", i))
+		}
+		content.Sections["projects"] = map[string]interface{}{"items": items}
+		c.from("sections.projects", "projects")
+	}
+
+	if len(resume.Education) > 0 {
+		items := make([]interface{}, len(resume.Education))
+		for i, education := range resume.Education {
+			items[i] = entryValue(map[string]string{
+				"degree":      formatDegree(education),
+				"institution": education.Institution,
+				"start_date":  formatResumeDate(education.StartDate),
+				"end_date":    formatEndDate(education.StartDate, education.EndDate),
+			}, nil)
+			c.fromItem("education", i, indexPath("education", i))
+		}
+		content.Sections["education"] = map[string]interface{}{"items": items}
+		c.from("sections.education", "education")
+	}
+
+	if len(resume.Skills) > 0 {
+		skills := make([]interface{}, len(resume.Skills))
+		for i, skill := range resume.Skills {
+			line := skill.Name
+			if len(skill.Keywords) > 0 {
+				line += ": " + strings.Join(skill.Keywords, ", ")
+			}
+			skills[i] = line
+			c.from(indexPath("sections.skills", i), indexPath("skills", i))
+		}
+		content.Sections["skills"] = skills
+		c.from("sections.skills", "skills")
+	}
+
+	if len(resume.Languages) > 0 {
+		languages := make([]interface{}, len(resume.Languages))
+		for i, language := range resume.Languages {
+			languages[i] = formatLanguage(language)
+			c.from(indexPath("sections.languages", i), indexPath("languages", i))
+		}
+		content.Sections["languages"] = languages
+		c.from("sections.languages", "languages")
+	}
+
+	if len(resume.Certificates) > 0 {
+		certifications := make([]interface{}, len(resume.Certificates))
+		for i, certificate := range resume.Certificates {
+			certifications[i] = formatCertificate(certificate)
+			c.from(indexPath("sections.certifications", i), indexPath("certificates", i))
+		}
+		content.Sections["certifications"] = certifications
+		c.from("sections.certifications", "certificates")
+	}
+
+	return content
+}
+
+func (c *jsonResumeConverter) fromItem(section string, index int, docPath string) {
+	c.from(indexPath("sections."+section+".items", index), docPath)
+}
+
+// contactFields generates the contact line: address, email and phone
+// through placeholders, then the website and every profile as links
+func (c *jsonResumeConverter) contactFields(basics JSONResumeBasics) []ContactField {
+	var fields []ContactField
+	add := func(field ContactField, docPath string) {
+		c.from(indexPath("contact_fields", len(fields)), docPath)
+		fields = append(fields, field)
+	}
+
+	if formatLocation(basics.Location) != "" {
+		add(ContactField{Field: "address", Content: "{{.Personal.Address}}", Icon: "address"}, "basics.location")
+	}
+	if basics.Email != "" {
+		add(ContactField{Field: "email", Content: "{{.Personal.Email}}", Icon: "email"}, "basics.email")
+	}
+	if basics.Phone != "" {
+		add(ContactField{Field: "phone", Content: "{{.Personal.Phone}}", Icon: "phone"}, "basics.phone")
+	}
+	if basics.URL != "" {
+		add(linkField("website", displayURL(basics.URL), "website", basics.URL), "basics.url")
+	}
+
+	for i, profile := range basics.Profiles {
+		if profile.URL == "" {
+			continue
+		}
+		network := strings.ToLower(profile.Network)
+		label := firstNonEmpty(profile.Username, displayURL(profile.URL))
+
+		// Only networks with a stock icon get their own; the rest share the globe
+		icon := "globe"
+		if network == "github" || network == "linkedin" {
+			icon = network
+		}
+		add(linkField(network, label, icon, profile.URL), indexPath("basics.profiles", i))
+	}
+	return fields
+}
+
+func linkField(field, label, icon, link string) ContactField {
+	linkType := "link"
+	return ContactField{Field: field, Content: label, Icon: icon, Link: &link, Type: &linkType}
+}
+
+// entryValue builds an entry_list item, leaving out empty fields
+func entryValue(fields map[string]string, description []string) map[string]interface{} {
+	entry := make(map[string]interface{})
+	for key, value := range fields {
+		if value != "" {
+			entry[key] = value
+		}
+	}
+	if len(description) > 0 {
+		lines := make([]interface{}, len(description))
+		for i, line := range description {
+			lines[i] = line
+		}
+		entry["description"] = lines
+	}
+	return entry
+}
+
+// formatLocation joins a location the way addresses are usually written,
+// e.g. "Houston, TX 77064"
+func formatLocation(location *JSONResumeLocation) string {
+	if location == nil {
+		return ""
+	}
+	region := strings.TrimSpace(location.Region + " " + location.PostalCode)
+	parts := []string{}
+	for _, part := range []string{location.Address, location.City, region} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return location.CountryCode
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatDegree(education JSONResumeEducation) string {
+	switch {
+	case education.StudyType != "" && education.Area != "":
+		return education.StudyType + " in " + education.Area
+	case education.StudyType != "":
+		return education.StudyType
+	default:
+		return education.Area
+	}
+}
+
+// formatLanguage writes a language the way the languages section lists
+// them, as in "Spanish (B2)"
+func formatLanguage(language JSONResumeLanguage) string {
+	if language.Fluency == "" {
+		return language.Language
+	}
+	return language.Language + " (" + language.Fluency + ")"
+}
+
+func formatCertificate(certificate JSONResumeCertificate) string {
+	line := certificate.Name
+	if certificate.Issuer != "" {
+		line += ", " + certificate.Issuer
+	}
+	if date := formatResumeDate(certificate.Date); date != "" {
+		line += " (" + date + ")"
+	}
+	return line
+}
+
+// formatResumeDate turns ISO 8601 dates ("2021-06-01", "2021-06") into the
+// "Jun 2021" style used in content files. Anything else is kept as is.
+func formatResumeDate(date string) string {
+	for _, layout := range []string{"2006-01-02", "2006-01"} {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed.Format("Jan 2006")
+		}
+	}
+	return date
+}
+
+// formatEndDate marks entries that started but never ended as ongoing
+func formatEndDate(start, end string) string {
+	if end == "" && start != "" {
+		return "Present"
+	}
+	return formatResumeDate(end)
+}
+
+// displayURL shortens a URL for display, e.g. "https://www.example.com/" to "example.com"
+func displayURL(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		return link
+	}
+	host := strings.TrimPrefix(parsed.Host, "www.")
+	return strings.TrimSuffix(host+parsed.Path, "/")
+}
+
+// JSONResumeSchema is the schema exported documents declare
+const JSONResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// jsonResumeMetaKey holds sections JSON Resume has no place for, under meta
+const jsonResumeMetaKey = "resumeBuilder"
+
+// ExportJSONResume converts content to a JSON Resume document. Sections the
+// schema lacks are kept under meta.resumeBuilder.sections. Anything that
+// can't be carried over is returned as warnings located in src.
+func ExportJSONResume(content *Content, src *Source) (*JSONResume, Problems) {
+	exp := jsonResumeExporter{src: src}
+	resume := exp.export(content)
+	return resume, exp.dropped.sorted(sourceFile(src))
+}
+
+type jsonResumeExporter struct {
+	src     *Source
+	dropped Problems
+}
+
+func (e *jsonResumeExporter) drop(path, format string, args ...interface{}) {
+	problem := locatedProblem(e.src, path, fmt.Sprintf(format, args...))
+	problem.Severity = SeverityWarning
+	problem.Code = "not-exported"
+	problem.Section = sectionOf(path)
+	e.dropped = append(e.dropped, problem)
+}
+
+func (e *jsonResumeExporter) export(content *Content) *JSONResume {
+	personal := content.Personal
+	resume := &JSONResume{
+		Schema: JSONResumeSchema,
+		Basics: JSONResumeBasics{
+			Name:  personal.Name,
+			Email: personal.Email,
+			Phone: personal.Phone,
+			URL:   personal.Website,
+		},
+	}
+	if personal.Address != "" {
+		resume.Basics.Location = &JSONResumeLocation{Address: personal.Address}
+	}
+
+	resume.Basics.Profiles = e.profiles(content, &resume.Basics)
+
+	extra := make(map[string]interface{})
+	for _, key := range SortedKeys(content.Sections) {
+		path := "sections." + key
+		data := content.Sections[key]
+		switch key {
+		case "summary":
+			resume.Basics.Summary = e.text(path, data)
+		case "experience":
+			for _, entry := range e.entries(path, data) {
+				work := JSONResumeWork{
+					Position:   entry.text("title"),
+					Name:       entry.text("company"),
+					Location:   entry.text("location"),
+					StartDate:  e.date(entry.path+".start_date", entry.text("start_date")),
+					EndDate:    e.date(entry.path+".end_date", entry.text("end_date")),
+					Highlights: entry.description(),
+				}
+				e.dropFields(entry, "degree", "institution")
+				resume.Work = append(resume.Work, work)
+			}
+		case "projects":
+			for _, entry := range e.entries(path, data) {
+				project := JSONResumeProject{
+					Name:       entry.text("title"),
+					Entity:     entry.text("company"),
+					StartDate:  e.date(entry.path+".start_date", entry.text("start_date")),
+					EndDate:    e.date(entry.path+".end_date", entry.text("end_date")),
+					Highlights: entry.description(),
+				}
+				e.dropFields(entry, "location", "degree", "institution")
+				resume.Projects = append(resume.Projects, project)
+			}
+		case "education":
+			for _, entry := range e.entries(path, data) {
+				studyType, area := splitDegree(entry.text("degree"))
+				education := JSONResumeEducation{
+					Institution: entry.text("institution"),
+					StudyType:   studyType,
+					Area:        area,
+					StartDate:   e.date(entry.path+".start_date", entry.text("start_date")),
+					EndDate:     e.date(entry.path+".end_date", entry.text("end_date")),
+				}
+				e.dropFields(entry, "title", "company", "location", "description")
+				resume.Education = append(resume.Education, education)
+			}
+		case "skills":
+			for _, line := range e.lines(path, data) {
+				resume.Skills = append(resume.Skills, parseSkill(line))
+			}
+		case "languages":
+			for _, line := range e.lines(path, data) {
+				resume.Languages = append(resume.Languages, parseLanguage(line))
+			}
+		case "certifications":
+			for _, line := range e.lines(path, data) {
+				resume.Certificates = append(resume.Certificates, parseCertificate(line))
+			}
+		default:
+			extra[key] = data
+		}
+	}
+
+	if len(extra) > 0 {
+		resume.Meta = map[string]interface{}{
+			jsonResumeMetaKey: map[string]interface{}{"sections": extra},
+		}
+	}
+	return resume
+}
+
+// profiles exports the GitHub and LinkedIn URLs of the personal info and
+// turns the other link contact fields into profiles. A link field for one
+// of those URLs only supplies its username. Fields that only show personal
+// info are covered by basics; anything else is dropped.
+func (e *jsonResumeExporter) profiles(content *Content, basics *JSONResumeBasics) []JSONResumeProfile {
+	var profiles []JSONResumeProfile
+	for _, personal := range []struct{ network, url string }{
+		{"GitHub", content.Personal.GitHub},
+		{"LinkedIn", content.Personal.LinkedIn},
+	} {
+		if personal.url != "" {
+			profiles = append(profiles, JSONResumeProfile{
+				Network:  personal.network,
+				Username: profileUsername(personal.url),
+				URL:      personal.url,
+			})
+		}
+	}
+
+	for i, field := range content.ContactFields {
+		path := indexPath("contact_fields", i)
+		switch {
+		case field.Link != nil && *field.Link != "":
+			link := ResolveTemplate(*field.Link, content)
+			if link == basics.URL {
+				continue
+			}
+			if field.Field == "website" && basics.URL == "" {
+				basics.URL = link
+				continue
+			}
+			if existing := findProfile(profiles, link); existing != nil {
+				if !strings.Contains(field.Content, "{{") {
+					existing.Username = field.Content
+				}
+				continue
+			}
+			profiles = append(profiles, JSONResumeProfile{
+				Network:  profileNetwork(field.Field),
+				Username: field.Content,
+				URL:      link,
+			})
+		case strings.Contains(field.Content, "{{"):
+			// A placeholder for personal info, already exported in basics
+		default:
+			e.drop(path, "contact field %q has no JSON Resume equivalent", field.Field)
+		}
+	}
+	return profiles
+}
+
+func findProfile(profiles []JSONResumeProfile, link string) *JSONResumeProfile {
+	for i := range profiles {
+		if profiles[i].URL == link {
+			return &profiles[i]
+		}
+	}
+	return nil
+}
+
+// profileUsername is the last segment of a profile URL's path, as in
+// "jane-doe" for https://linkedin.com/in/jane-doe
+func profileUsername(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	return segments[len(segments)-1]
+}
+
+// exportEntry is one entry_list item being exported
+type exportEntry struct {
+	path   string
+	fields map[string]interface{}
+}
+
+func (entry exportEntry) text(key string) string {
+	value, _ := entry.fields[key].(string)
+	return value
+}
+
+func (entry exportEntry) description() []string {
+	lines, _ := entry.fields["description"].([]interface{})
+	var description []string
+	for _, line := range lines {
+		if text, ok := line.(string); ok {
+			description = append(description, text)
+		}
+	}
+	return description
+}
+
+func (e *jsonResumeExporter) entries(path string, data interface{}) []exportEntry {
+	object, _ := data.(map[string]interface{})
+	items, ok := object["items"].([]interface{})
+	if !ok {
+		e.drop(path, "expected an entry list with items")
+		return nil
+	}
+
+	var entries []exportEntry
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			e.drop(indexPath(path+".items", i), "expected an entry, found %s", describeValue(item))
+			continue
+		}
+		entries = append(entries, exportEntry{path: indexPath(path+".items", i), fields: fields})
+	}
+	return entries
+}
+
+// dropFields reports entry fields the target JSON Resume entry can't hold
+func (e *jsonResumeExporter) dropFields(entry exportEntry, keys ...string) {
+	for _, key := range keys {
+		if _, ok := entry.fields[key]; ok {
+			e.drop(joinPath(entry.path, key), "field %q has no JSON Resume equivalent in this section", key)
+		}
+	}
+}
+
+// text reads a simple_list section as one paragraph
+func (e *jsonResumeExporter) text(path string, data interface{}) string {
+	if object, ok := data.(map[string]interface{}); ok {
+		if text, ok := object["content"].(string); ok {
+			return text
+		}
+	}
+	return strings.Join(e.lines(path, data), " ")
+}
+
+// lines reads a simple_list section as a list of strings
+func (e *jsonResumeExporter) lines(path string, data interface{}) []string {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		if text, ok := value["content"].(string); ok {
+			return []string{text}
+		}
+	case []interface{}:
+		var lines []string
+		for i, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				e.drop(indexPath(path, i), "expected a string, found %s", describeValue(item))
+				continue
+			}
+			lines = append(lines, text)
+		}
+		return lines
+	}
+	e.drop(path, "expected a content string or a list of strings, found %s", describeValue(data))
+	return nil
+}
+
+// date converts "Jun 2021" and "2021" to the ISO 8601 form JSON Resume
+// expects. Ongoing entries ("Present") have no end date.
+func (e *jsonResumeExporter) date(path, date string) string {
+	switch strings.ToLower(strings.TrimSpace(date)) {
+	case "", "present", "current", "now":
+		return ""
+	}
+	for _, layout := range []string{"Jan 2006", "January 2006", "2006-01-02", "2006-01", "2006"} {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			switch layout {
+			case "2006", "2006-01-02":
+				return date
+			}
+			return parsed.Format("2006-01")
+		}
+	}
+	e.drop(path, "date %q is not in a form JSON Resume accepts", date)
+	return ""
+}
+
+// splitDegree reverses formatDegree: "Bachelor of Science in Physics"
+// becomes study type "Bachelor of Science" and area "Physics"
+func splitDegree(degree string) (studyType, area string) {
+	if cut := strings.LastIndex(degree, " in "); cut > 0 {
+		return degree[:cut], degree[cut+len(" in "):]
+	}
+	return degree, ""
+}
+
+// parseSkill reverses the "Name: keyword, keyword" lines the importer writes
+func parseSkill(line string) JSONResumeSkill {
+	name, keywords, found := strings.Cut(line, ": ")
+	if !found {
+		return JSONResumeSkill{Name: line}
+	}
+	skill := JSONResumeSkill{Name: name}
+	for _, keyword := range strings.Split(keywords, ",") {
+		skill.Keywords = append(skill.Keywords, strings.TrimSpace(keyword))
+	}
+	return skill
+}
+
+// parseLanguage reverses formatLanguage: "Spanish (B2)" becomes language
+// "Spanish" with fluency "B2"
+func parseLanguage(line string) JSONResumeLanguage {
+	open := strings.LastIndex(line, " (")
+	if open < 0 || !strings.HasSuffix(line, ")") {
+		return JSONResumeLanguage{Language: line}
+	}
+	return JSONResumeLanguage{Language: line[:open], Fluency: line[open+2 : len(line)-1]}
+}
+
+// parseCertificate pulls a trailing "(Sep 2022)" date back out of a
+// certification line
+func parseCertificate(line string) JSONResumeCertificate {
+	open := strings.LastIndex(line, " (")
+	if open < 0 || !strings.HasSuffix(line, ")") {
+		return JSONResumeCertificate{Name: line}
+	}
+	parsed, err := time.Parse("Jan 2006", line[open+2:len(line)-1])
+	if err != nil {
+		return JSONResumeCertificate{Name: line}
+	}
+	return JSONResumeCertificate{Name: line[:open], Date: parsed.Format("2006-01")}
+}
+
+// profileNetwork names well-known networks the way JSON Resume tools expect
+func profileNetwork(field string) string {
+	switch strings.ToLower(field) {
+	case "github":
+		return "GitHub"
+	case "linkedin":
+		return "LinkedIn"
+	}
+	return field
+}
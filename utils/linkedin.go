@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// linkedInRecord is one CSV row keyed by column name
+type linkedInRecord map[string]string
+
+// ImportLinkedIn converts the archive from LinkedIn's "download your data"
+// page. Profile.csv is required; Positions, Education, Skills,
+// Certifications, Email Addresses and PhoneNumbers are used when present.
+func ImportLinkedIn(filename string) (*Content, error) {
+	data, err := ReadInput(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: not a LinkedIn data archive: %w", DisplayName(filename), err)
+	}
+
+	read := func(name, column string) ([]linkedInRecord, error) {
+		records, err := readLinkedInCSV(archive, name, column)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", DisplayName(filename), name, err)
+		}
+		return records, nil
+	}
+
+	profiles, err := read("Profile.csv", "First Name")
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("%s: Profile.csv is missing or empty", DisplayName(filename))
+	}
+
+	content := &Content{Sections: make(map[string]interface{})}
+	profile := profiles[0]
+	content.Personal.Name = strings.TrimSpace(profile["First Name"] + " " + profile["Last Name"])
+	content.Personal.Address = firstNonEmpty(profile["Geo Location"], profile["Address"])
+	content.Personal.Website = linkedInWebsite(profile["Websites"])
+
+	emails, err := read("Email Addresses.csv", "Email Address")
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range emails {
+		if content.Personal.Email == "" || email["Primary"] == "Yes" {
+			content.Personal.Email = email["Email Address"]
+		}
+	}
+
+	phones, err := read("PhoneNumbers.csv", "Number")
+	if err != nil {
+		return nil, err
+	}
+	if len(phones) > 0 {
+		content.Personal.Phone = phones[0]["Number"]
+	}
+
+	content.ContactFields = personalContactFields(content.Personal)
+
+	if summary := firstNonEmpty(profile["Summary"], profile["Headline"]); summary != "" {
+		content.Sections["summary"] = map[string]interface{}{"content": summary}
+	}
+
+	positions, err := read("Positions.csv", "Company Name")
+	if err != nil {
+		return nil, err
+	}
+	if len(positions) > 0 {
+		items := make([]interface{}, len(positions))
+		for i, position := range positions {
+			items[i] = entryValue(map[string]string{
+				"title":      position["Title"],
+				"company":    position["Company Name"],
+				"location":   position["Location"],
+				"start_date": position["Started On"],
+				"end_date":   formatEndDate(position["Started On"], position["Finished On"]),
+			}, descriptionLines(position["Description"]))
+		}
+		content.Sections["experience"] = map[string]interface{}{"items": items}
+	}
+
+	schools, err := read("Education.csv", "School Name")
+	if err != nil {
+		return nil, err
+	}
+	if len(schools) > 0 {
+		items := make([]interface{}, len(schools))
+		for i, school := range schools {
+			items[i] = entryValue(map[string]string{
+				"degree":      school["Degree Name"],
+				"institution": school["School Name"],
+				"start_date":  school["Start Date"],
+				"end_date":    school["End Date"],
+			}, descriptionLines(school["Notes"]))
+		}
+		content.Sections["education"] = map[string]interface{}{"items": items}
+	}
+
+	skills, err := read("Skills.csv", "Name")
+	if err != nil {
+		return nil, err
+	}
+	if len(skills) > 0 {
+		list := make([]interface{}, len(skills))
+		for i, skill := range skills {
+			list[i] = skill["Name"]
+		}
+		content.Sections["skills"] = list
+	}
+
+	certifications, err := read("Certifications.csv", "Name")
+	if err != nil {
+		return nil, err
+	}
+	if len(certifications) > 0 {
+		list := make([]interface{}, len(certifications))
+		for i, certification := range certifications {
+			line := certification["Name"]
+			if authority := certification["Authority"]; authority != "" {
+				line += ", " + authority
+			}
+			list[i] = line
+		}
+		content.Sections["certifications"] = list
+	}
+
+	return content, nil
+}
+
+// readLinkedInCSV reads one CSV from the archive, wherever it sits in it.
+// A missing file gives no records. Some exports put notes above the
+// header, so rows before the first one naming column are skipped.
+func readLinkedInCSV(archive *zip.Reader, name, column string) ([]linkedInRecord, error) {
+	var file *zip.File
+	for _, f := range archive.File {
+		if strings.EqualFold(path.Base(f.Name), name) {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil, nil
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header []string
+	var records []linkedInRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header == nil {
+			row[0] = strings.TrimPrefix(row[0], "\ufeff")
+			for _, cell := range row {
+				if strings.TrimSpace(cell) == column {
+					header = row
+				}
+			}
+			continue
+		}
+
+		record := make(linkedInRecord)
+		for i, key := range header {
+			if i < len(row) {
+				record[strings.TrimSpace(key)] = strings.TrimSpace(row[i])
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// linkedInWebsite picks the first URL from the Websites column, which
+// looks like "[PORTFOLIO:https://example.com,BLOG:https://...]"
+func linkedInWebsite(websites string) string {
+	websites = strings.Trim(websites, "[]")
+	for _, site := range strings.Split(websites, ",") {
+		if i := strings.Index(site, "http"); i >= 0 {
+			return strings.TrimSpace(site[i:])
+		}
+	}
+	return ""
+}
+
+// descriptionLines splits free text into bullet lines, dropping bullet marks
+func descriptionLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-•*"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// personalContactFields generates the contact line for the personal info
+// that is filled in: address, email and phone through placeholders, then
+// the website, GitHub and LinkedIn links
+func personalContactFields(personal PersonalInfo) []ContactField {
+	var fields []ContactField
+	if personal.Address != "" {
+		fields = append(fields, ContactField{Field: "address", Content: "{{.Personal.Address}}", Icon: "address"})
+	}
+	if personal.Email != "" {
+		fields = append(fields, ContactField{Field: "email", Content: "{{.Personal.Email}}", Icon: "email"})
+	}
+	if personal.Phone != "" {
+		fields = append(fields, ContactField{Field: "phone", Content: "{{.Personal.Phone}}", Icon: "phone"})
+	}
+	if personal.Website != "" {
+		fields = append(fields, linkField("website", displayURL(personal.Website), "website", personal.Website))
+	}
+	if personal.GitHub != "" {
+		fields = append(fields, linkField("github", displayURL(personal.GitHub), "github", personal.GitHub))
+	}
+	if personal.LinkedIn != "" {
+		fields = append(fields, linkField("linkedin", displayURL(personal.LinkedIn), "linkedin", personal.LinkedIn))
+	}
+	return fields
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Manifest lists resume variants to build in one run
+type Manifest struct {
+	Jobs     int              `json:"jobs,omitempty"`
+	Defaults ManifestTarget   `json:"defaults,omitempty"`
+	Targets  []ManifestTarget `json:"targets"`
+}
+
+// ManifestTarget is one resume variant. Empty fields fall back to the
+// manifest defaults. Overrides replace config values by dotted path.
+type ManifestTarget struct {
+	Name      string                 `json:"name,omitempty"`
+	Input     string                 `json:"input,omitempty"`
+	Config    string                 `json:"config,omitempty"`
+	Template  string                 `json:"template,omitempty"`
+	Format    string                 `json:"format,omitempty"`
+	Output    string                 `json:"output,omitempty"`
+	Overrides map[string]interface{} `json:"overrides,omitempty"`
+}
+
+// LoadManifest reads a JSON, YAML or TOML manifest, fills in defaults and
+// resolves file paths relative to the manifest's directory. fallback fills
+// in the template and format neither a target nor the manifest defaults
+// set, ahead of the built-in ones, and its overrides give way to theirs.
+func LoadManifest(filename string, fallback ManifestTarget) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	src, err := decodeFile(filename, data, &manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(manifest.Targets) == 0 {
+		return nil, fmt.Errorf("%s: no targets", filename)
+	}
+
+	baseDir := filepath.Dir(filename)
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(baseDir, path)
+	}
+
+	var problems Problems
+	outputs := make(map[string]string)
+	for i := range manifest.Targets {
+		target := &manifest.Targets[i]
+		path := indexPath("targets", i)
+		defaults := manifest.Defaults
+
+		target.Input = resolve(firstNonEmpty(target.Input, defaults.Input))
+		target.Config = resolve(firstNonEmpty(target.Config, defaults.Config, "config.json"))
+		target.Template = firstNonEmpty(target.Template, defaults.Template, fallback.Template, "template-1")
+		target.Format = firstNonEmpty(target.Format, defaults.Format, fallback.Format, "pdf")
+		target.Output = resolve(target.Output)
+		if target.Name == "" {
+			target.Name = strings.TrimSuffix(filepath.Base(target.Output), filepath.Ext(target.Output))
+		}
+
+		// Target overrides win over default overrides, which win over
+		// the fallback's
+		overrides := make(map[string]interface{})
+		for key, value := range fallback.Overrides {
+			overrides[key] = value
+		}
+		for key, value := range defaults.Overrides {
+			overrides[key] = value
+		}
+		for key, value := range target.Overrides {
+			overrides[key] = value
+		}
+		target.Overrides = overrides
+
+		if target.Input == "" {
+			problems = append(problems, manifestProblem(src, path, "missing-input", "missing input"))
+		}
+		if target.Output == "" {
+			problems = append(problems, manifestProblem(src, path, "missing-output", "missing output"))
+		} else if other, taken := outputs[target.Output]; taken {
+			problems = append(problems, manifestProblem(src, path+".output", "duplicate-output", fmt.Sprintf("output %s is also written by target %q", target.Output, other)))
+		} else {
+			outputs[target.Output] = target.Name
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return &manifest, nil
+}
+
+func manifestProblem(src *Source, path, code, message string) Problem {
+	problem := locatedProblem(src, path, message)
+	problem.Code = code
+	return problem
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
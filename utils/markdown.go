@@ -0,0 +1,415 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownBullet   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+	markdownAutoLink = regexp.MustCompile(`<(https?://[^>]+)>`)
+	emailPattern     = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	phonePattern     = regexp.MustCompile(`^\+?[\d\s().-]{7,}$`)
+	// datePattern matches one side of a date range: a month or season and
+	// year, a numeric month, a bare year or an open end
+	datePattern = regexp.MustCompile(`(?i)^((jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+\d{4}|(spring|summer|fall|autumn|winter)\s+\d{4}|\d{1,2}/\d{4}|\d{4}-\d{2}|\d{4}|present|current|now)$`)
+)
+
+// markdownEmphasis matches bold, italic and code spans, strongest first
+var markdownEmphasis = []*regexp.Regexp{
+	regexp.MustCompile(`\*\*(.+?)\*\*`),
+	regexp.MustCompile(`__(.+?)__`),
+	regexp.MustCompile(`\*(.+?)\*`),
+	regexp.MustCompile(`\b_(.+?)_\b`),
+	regexp.MustCompile("`(.+?)`"),
+}
+
+// markdownSections maps common heading names to section keys
+var markdownSections = map[string]string{
+	"summary":         "summary",
+	"about":           "summary",
+	"about me":        "summary",
+	"profile":         "summary",
+	"experience":      "experience",
+	"work experience": "experience",
+	"employment":      "experience",
+	"work history":    "experience",
+	"projects":        "projects",
+	"education":       "education",
+	"skills":          "skills",
+	"languages":       "languages",
+	"certifications":  "certifications",
+	"certificates":    "certifications",
+	"licenses":        "certifications",
+}
+
+// markdownContent converts a Markdown resume. The H1 is the name, the
+// lines between it and the first H2 are the contact details, every H2 is
+// a section and every H3 an entry written as
+// "Title - Company | Location | Dates". Bullets become descriptions.
+func markdownContent(filename string, data []byte) (*Content, *Source, error) {
+	conv := markdownConverter{
+		file:      filename,
+		positions: make(map[string]Position),
+		content:   &Content{Sections: make(map[string]interface{})},
+	}
+	if err := conv.convert(strings.Split(string(data), "\n")); err != nil {
+		return nil, nil, err
+	}
+
+	src := &Source{File: filename, Format: FormatMarkdown, Positions: conv.positions}
+	if err := src.setValue(conv.content); err != nil {
+		return nil, nil, err
+	}
+	return conv.content, src, nil
+}
+
+// ImportMarkdown reads a Markdown resume and converts it to content
+func ImportMarkdown(filename string) (*Content, error) {
+	data, err := ReadInput(filename)
+	if err != nil {
+		return nil, err
+	}
+	content, _, err := markdownContent(DisplayName(filename), data)
+	return content, err
+}
+
+type markdownConverter struct {
+	file      string
+	positions map[string]Position
+	content   *Content
+
+	// section being filled and the lines collected for it
+	section        string
+	paragraphs     []string
+	paragraphLines []int
+	bullets        []string
+	bulletLines    []int
+	entries        []interface{}
+	entry          map[string]interface{}
+}
+
+func (c *markdownConverter) at(path string, line int) {
+	c.positions[path] = Position{Line: line, Column: 1}
+}
+
+func (c *markdownConverter) convert(lines []string) error {
+	inContact := false
+	for i, raw := range lines {
+		lineNo := i + 1
+		line := strings.TrimRight(raw, " \t\r")
+
+		if match := markdownHeading.FindStringSubmatch(line); match != nil {
+			level, title := len(match[1]), match[2]
+			switch level {
+			case 1:
+				c.content.Personal.Name = plainMarkdown(title)
+				c.at("personal.name", lineNo)
+				inContact = true
+				continue
+			case 2:
+				c.finishSection()
+				inContact = false
+				c.startSection(title, lineNo)
+				continue
+			case 3:
+				if c.section != "" {
+					c.startEntry(title, lineNo)
+					continue
+				}
+			}
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if inContact {
+			c.contactLine(line, lineNo)
+			continue
+		}
+		if c.section == "" {
+			continue
+		}
+
+		if match := markdownBullet.FindStringSubmatch(line); match != nil {
+			c.bullets = append(c.bullets, plainMarkdown(match[1]))
+			c.bulletLines = append(c.bulletLines, lineNo)
+			continue
+		}
+
+		// Indented lines continue the previous bullet
+		if len(c.bullets) > 0 && strings.HasPrefix(raw, "  ") {
+			last := len(c.bullets) - 1
+			c.bullets[last] += " " + plainMarkdown(strings.TrimSpace(line))
+			continue
+		}
+		c.paragraphs = append(c.paragraphs, plainMarkdown(strings.TrimSpace(line)))
+		c.paragraphLines = append(c.paragraphLines, lineNo)
+	}
+	c.finishSection()
+
+	if c.content.Personal.Name == "" {
+		return Problem{Severity: SeverityError, Code: "missing-name", File: c.file, Message: "a Markdown resume starts with an H1 holding the name"}
+	}
+	return nil
+}
+
+func (c *markdownConverter) startSection(title string, line int) {
+	name := strings.ToLower(plainMarkdown(title))
+	key, ok := markdownSections[name]
+	if !ok {
+		key = strings.ReplaceAll(name, " ", "_")
+	}
+	c.section = key
+	c.at("sections."+key, line)
+}
+
+// finishSection stores the collected lines: entries when the section has
+// H3s, a paragraph when it only has text, otherwise a list of strings
+func (c *markdownConverter) finishSection() {
+	if c.section == "" {
+		return
+	}
+	c.finishEntry()
+
+	path := "sections." + c.section
+	switch {
+	case len(c.entries) > 0:
+		c.content.Sections[c.section] = map[string]interface{}{"items": c.entries}
+	case len(c.bullets) > 0:
+		items := make([]interface{}, len(c.bullets))
+		for i, bullet := range c.bullets {
+			items[i] = bullet
+			c.at(indexPath(path, i), c.bulletLines[i])
+		}
+		c.content.Sections[c.section] = items
+	case len(c.paragraphs) > 0:
+		c.content.Sections[c.section] = map[string]interface{}{"content": strings.Join(c.paragraphs, " ")}
+	}
+
+	c.section, c.entries = "", nil
+	c.clearLines()
+}
+
+func (c *markdownConverter) clearLines() {
+	c.paragraphs, c.paragraphLines, c.bullets, c.bulletLines = nil, nil, nil, nil
+}
+
+// startEntry parses "Title - Company | Location | Dates". Education
+// entries name a degree and institution instead.
+func (c *markdownConverter) startEntry(heading string, line int) {
+	if c.entry == nil {
+		c.dropIntro()
+	}
+	c.finishEntry()
+
+	parts := strings.Split(plainMarkdown(heading), "|")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	titleKey, orgKey := "title", "company"
+	if c.section == "education" {
+		titleKey, orgKey = "degree", "institution"
+	}
+
+	c.entry = make(map[string]interface{})
+	title, org := splitEntryHeading(parts[0])
+	c.entry[titleKey] = title
+	if org != "" {
+		c.entry[orgKey] = org
+	}
+
+	for _, part := range parts[1:] {
+		if isDateRange(part) {
+			start, end := splitDates(part)
+			if start != "" {
+				c.entry["start_date"] = start
+			}
+			if end != "" {
+				c.entry["end_date"] = end
+			}
+		} else if part != "" {
+			c.entry["location"] = part
+		}
+	}
+
+	c.at(indexPath("sections."+c.section+".items", len(c.entries)), line)
+	c.clearLines()
+}
+
+// dropIntro reports text between a section heading and its first entry.
+// Entry lists have no place for it, so it is not imported.
+func (c *markdownConverter) dropIntro() {
+	lines := append(append([]int(nil), c.paragraphLines...), c.bulletLines...)
+	if len(lines) == 0 {
+		return
+	}
+	first := lines[0]
+	for _, line := range lines {
+		first = min(first, line)
+	}
+	Warn(Problem{Code: "not-imported", File: c.file, Line: first, Column: 1, Path: "sections." + c.section,
+		Message: "text before the first entry of the section has no place in the content and was not imported"})
+	c.clearLines()
+}
+
+// finishEntry attaches the text and bullets under an H3 as its description
+func (c *markdownConverter) finishEntry() {
+	if c.entry == nil {
+		return
+	}
+	path := indexPath("sections."+c.section+".items", len(c.entries))
+
+	var description []interface{}
+	for _, paragraph := range c.paragraphs {
+		description = append(description, paragraph)
+	}
+	for i, bullet := range c.bullets {
+		c.at(indexPath(path+".description", len(description)), c.bulletLines[i])
+		description = append(description, bullet)
+	}
+	if len(description) > 0 {
+		c.entry["description"] = description
+	}
+
+	c.entries = append(c.entries, c.entry)
+	c.entry = nil
+	c.clearLines()
+}
+
+// contactLine reads contact details written as one line separated by
+// "|", "·" or "•", or as a list with one detail per item. Items may be
+// labelled, as in "Email: jane@example.com".
+func (c *markdownConverter) contactLine(line string, lineNo int) {
+	if match := markdownBullet.FindStringSubmatch(line); match != nil {
+		line = match[1]
+	}
+	line = markdownAutoLink.ReplaceAllString(line, "[$1]($1)")
+
+	for _, item := range strings.FieldsFunc(line, func(r rune) bool { return r == '|' || r == '·' || r == '•' }) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			c.contactItem(item, lineNo)
+		}
+	}
+}
+
+func (c *markdownConverter) contactItem(item string, lineNo int) {
+	personal := &c.content.Personal
+	path := indexPath("contact_fields", len(c.content.ContactFields))
+
+	label := ""
+	if name, value, found := strings.Cut(item, ":"); found && !strings.HasPrefix(value, "//") {
+		label, item = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+	}
+
+	if match := markdownLink.FindStringSubmatch(item); match != nil {
+		text, link := plainMarkdown(match[1]), match[2]
+		if strings.HasPrefix(link, "mailto:") {
+			item = strings.TrimPrefix(link, "mailto:")
+		} else {
+			field := linkNetwork(link, label)
+			switch field {
+			case "github":
+				personal.GitHub = link
+			case "linkedin":
+				personal.LinkedIn = link
+			case "website":
+				personal.Website = link
+			}
+			icon := field
+			if icon != "github" && icon != "linkedin" && icon != "website" {
+				icon = "globe"
+			}
+			c.at(path, lineNo)
+			c.content.ContactFields = append(c.content.ContactFields, linkField(field, firstNonEmpty(text, displayURL(link)), icon, link))
+			return
+		}
+	}
+
+	item = plainMarkdown(item)
+	var field ContactField
+	switch {
+	case label == "email" || emailPattern.MatchString(item):
+		personal.Email = item
+		field = ContactField{Field: "email", Content: "{{.Personal.Email}}", Icon: "email"}
+		c.at("personal.email", lineNo)
+	case label == "phone" || phonePattern.MatchString(item):
+		personal.Phone = item
+		field = ContactField{Field: "phone", Content: "{{.Personal.Phone}}", Icon: "phone"}
+		c.at("personal.phone", lineNo)
+	case strings.HasPrefix(item, "http://") || strings.HasPrefix(item, "https://"):
+		c.contactItem(fmt.Sprintf("[%s](%s)", displayURL(item), item), lineNo)
+		return
+	default:
+		personal.Address = item
+		field = ContactField{Field: "address", Content: "{{.Personal.Address}}", Icon: "address"}
+		c.at("personal.address", lineNo)
+	}
+	c.at(path, lineNo)
+	c.content.ContactFields = append(c.content.ContactFields, field)
+}
+
+// linkNetwork names a contact link after its site, unless it is labelled
+func linkNetwork(link, label string) string {
+	if label != "" {
+		return label
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "website"
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	switch {
+	case strings.HasSuffix(host, "github.com"):
+		return "github"
+	case strings.HasSuffix(host, "linkedin.com"):
+		return "linkedin"
+	}
+	return "website"
+}
+
+// splitEntryHeading splits "Title - Company" on the first dash
+func splitEntryHeading(heading string) (title, org string) {
+	for _, sep := range []string{" - ", " – ", " — ", " at "} {
+		if before, after, found := strings.Cut(heading, sep); found {
+			return strings.TrimSpace(before), strings.TrimSpace(after)
+		}
+	}
+	return heading, ""
+}
+
+// isDateRange reports whether a heading part is a date or a range of
+// dates, so a location such as "Seattle, WA 98101" isn't mistaken for one
+func isDateRange(part string) bool {
+	start, end := splitDates(part)
+	if end == "" {
+		return false
+	}
+	return (start == "" || datePattern.MatchString(start)) && datePattern.MatchString(end)
+}
+
+// splitDates splits "Jun 2021 - Present" into its start and end
+func splitDates(dates string) (start, end string) {
+	for _, sep := range []string{" - ", " – ", " — ", "–", " to "} {
+		if before, after, found := strings.Cut(dates, sep); found {
+			return strings.TrimSpace(before), strings.TrimSpace(after)
+		}
+	}
+	return "", strings.TrimSpace(dates)
+}
+
+// plainMarkdown strips links and emphasis, leaving the text
+func plainMarkdown(text string) string {
+	text = markdownLink.ReplaceAllString(text, "$1")
+	for _, pattern := range markdownEmphasis {
+		text = pattern.ReplaceAllString(text, "$1")
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadMarkdownContentFromStdin(t *testing.T) {
+	file, err := os.Open("../example-resume.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	stdin := os.Stdin
+	os.Stdin = file
+	t.Cleanup(func() { os.Stdin = stdin })
+
+	content, src, err := LoadContentSource(Stdio)
+	if err != nil {
+		t.Fatalf("LoadContentSource(stdin): %v", err)
+	}
+	if src.Format != FormatMarkdown {
+		t.Errorf("stdin read as %s, want %s", src.Format, FormatMarkdown)
+	}
+	if content.Personal.Name != "Jane Doe" {
+		t.Errorf("name %q, want %q", content.Personal.Name, "Jane Doe")
+	}
+	if _, ok := content.Sections["experience"]; !ok {
+		t.Errorf("sections %v have no experience", SortedKeys(content.Sections))
+	}
+}
+
+func TestDetectFormatKeepsCommentedYAML(t *testing.T) {
+	data, err := os.ReadFile("../example-resume.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commented := append([]byte("# Resume content\n## Edit the sections below\n"), data...)
+	if format := DetectFormat(Stdio, commented); format != FormatYAML {
+		t.Errorf("YAML with heading-like comments detected as %s", format)
+	}
+	if format := DetectFormat(Stdio, []byte(starterConfig)); format != FormatYAML {
+		t.Errorf("starter config detected as %s", format)
+	}
+}
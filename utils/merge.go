@@ -0,0 +1,47 @@
+package utils
+
+// MergeContent copies what an import produced into existing content. Filled
+// personal info fields replace the existing ones, contact fields replace
+// the existing field of the same name or are appended, and sections replace
+// the section with the same key. Everything else in dst is kept.
+func MergeContent(dst, src *Content) {
+	fields := []struct{ dst, src *string }{
+		{&dst.Personal.Name, &src.Personal.Name},
+		{&dst.Personal.Email, &src.Personal.Email},
+		{&dst.Personal.Phone, &src.Personal.Phone},
+		{&dst.Personal.Address, &src.Personal.Address},
+		{&dst.Personal.Website, &src.Personal.Website},
+		{&dst.Personal.GitHub, &src.Personal.GitHub},
+		{&dst.Personal.LinkedIn, &src.Personal.LinkedIn},
+	}
+	for _, field := range fields {
+		if *field.src != "" {
+			*field.dst = *field.src
+		}
+	}
+
+	// Each existing field is replaced at most once, so repeated fields such
+	// as two websites don't overwrite each other
+	existing := len(dst.ContactFields)
+	replacedAt := make(map[int]bool)
+	for _, field := range src.ContactFields {
+		replaced := false
+		for i := 0; i < existing; i++ {
+			if !replacedAt[i] && dst.ContactFields[i].Field == field.Field {
+				dst.ContactFields[i] = field
+				replacedAt[i], replaced = true, true
+				break
+			}
+		}
+		if !replaced {
+			dst.ContactFields = append(dst.ContactFields, field)
+		}
+	}
+
+	if dst.Sections == nil {
+		dst.Sections = make(map[string]interface{})
+	}
+	for key, section := range src.Sections {
+		dst.Sections[key] = section
+	}
+}
@@ -0,0 +1,366 @@
+package utils
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+)
+
+// Starter project file names written by WriteStarterProject
+const (
+	StarterConfigFile  = "config.yaml"
+	StarterContentFile = "resume.yaml"
+)
+
+// DefaultStarterPersonal is the placeholder personal info used when
+// init runs without prompting
+func DefaultStarterPersonal() PersonalInfo {
+	return PersonalInfo{
+		Name:    "Your Name",
+		Email:   "you@example.com",
+		Phone:   "(555) 555-0100",
+		Address: "City, ST 00000",
+	}
+}
+
+// WriteStarterProject writes a commented starter config, a sample content
+// file and the icon output directory into dir. Existing files are only
+// replaced when force is set. It returns the paths it created.
+func WriteStarterProject(dir string, personal PersonalInfo, force bool) ([]string, error) {
+	contentTmpl, err := template.New("content").
+		Delims("[[", "]]").
+		Funcs(template.FuncMap{"quote": strconv.Quote}).
+		Parse(starterContentTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var contentBuf bytes.Buffer
+	if err := contentTmpl.Execute(&contentBuf, personal); err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{StarterConfigFile, []byte(starterConfig)},
+		{StarterContentFile, contentBuf.Bytes()},
+	}
+
+	if !force {
+		for _, file := range files {
+			path := filepath.Join(dir, file.name)
+			if _, err := os.Stat(path); err == nil {
+				return nil, &fs.PathError{Op: "create", Path: path, Err: fs.ErrExist}
+			}
+		}
+	}
+
+	var created []string
+	iconDir := filepath.Join(dir, "icons")
+	if err := os.MkdirAll(iconDir, 0755); err != nil {
+		return created, err
+	}
+	created = append(created, iconDir+string(filepath.Separator))
+
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if err := os.WriteFile(path, file.data, 0644); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+
+	return created, nil
+}
+
+const starterConfig = `# Layout config for resume-builder.
+# Every value below is referenced by name from somewhere else in this file,
+# so renaming a key means updating the places that point at it.
+
+pdf:
+  page_size: A4
+  # Page margins in millimetres
+  margins:
+    top: 20
+    bottom: 20
+    left: 20
+    right: 20
+  background_color: "#FFFFFF"
+  # Write PDF/A-2b for archiving (same as build -pdfa)
+  # pdfa: true
+  # Document info: the title and subject take content placeholders, and
+  # the creation date (YYYY-MM-DD or RFC 3339) defaults to the build time
+  # title: "{{.Personal.Name}} - Resume"
+  # subject: Resume
+  # creation_date: "2024-01-31"
+
+# Plain-text output (build -format txt): the column lines wrap at and the
+# width of the contact label column
+text:
+  width: 80
+  label_width: 10
+
+# Named row heights (mm). Section templates refer to these names.
+spacing:
+  tiny: 5
+  small: 5
+  medium: 8      # also used for the divider under the contact block
+  large: 12
+  huge: 20
+  section_gap: 15
+
+# Named fonts. "section_title" and "emphasis" are used by every section;
+# the others are picked by the section templates below.
+fonts:
+  header:
+    family: Helvetica
+    size: 24
+    style: Bold          # Normal, Bold, Italic or BoldItalic
+    color: primary       # a name from colors, or a hex value
+  section_title:
+    family: Helvetica
+    size: 14
+    style: Bold
+    color: secondary
+  body:
+    family: Helvetica
+    size: 11
+    style: Normal
+    color: text
+  small:
+    family: Helvetica
+    size: 9
+    style: Normal
+    color: text
+  emphasis:
+    family: Helvetica
+    size: 11
+    style: Bold
+    color: text
+
+# Named colors. "secondary" draws the divider and "link" colors hyperlinks.
+colors:
+  primary: "#2C3E50"
+  secondary: "#2980B9"
+  text: "#000000"
+  link: "#0000FF"
+  accent: "#3498DB"
+  background: "#FFFFFF"
+
+icons:
+  # Directories searched for <name>.svg, relative to where you run the tool
+  svg_paths:
+    - fontawesome-free-6.4.0-desktop/svgs/solid
+    - fontawesome-free-6.4.0-desktop/svgs/brands
+  # Rendered PNGs are cached here
+  output_dir: icons
+  default_size: 32
+  color: primary
+  # Icon names used in sections and contact_fields -> SVG file names
+  mappings:
+    email: envelope
+    phone: phone
+    website: arrow-up-right-from-square
+    globe: arrow-up-right-from-square
+    github: github
+    linkedin: linkedin
+    address: house
+    experience: building
+    projects: diagram-project
+    education: graduation-cap
+    publications: book-open
+    skills: box
+    languages: language
+    certificate: certificate
+
+# How each kind of section is laid out
+section_templates:
+  header:
+    spacing: tiny
+    font: header
+  contact:
+    spacing: tiny
+    font: small
+    icon_size: 8         # icon width as a percentage of its column
+  entry_list:            # jobs, schools: title line, location/dates, bullets
+    spacing: tiny
+    font: body
+    title_spacing: tiny
+    item_spacing: tiny
+    icon_size: 8
+  simple_list:           # a paragraph or a " | " separated list of strings
+    spacing: tiny
+    font: body
+    title_spacing: tiny
+    icon_size: 8
+  publications:          # citations read from a .bib file
+    font: body
+    title_spacing: tiny
+    item_spacing: tiny
+    icon_size: 8
+
+# Which sections are printed, with which template, title and icon.
+# Set enabled to false to hide a section without deleting its content.
+sections:
+  header:
+    template: header
+    enabled: true
+  contact:
+    template: contact
+    enabled: true
+  summary:
+    template: simple_list
+    title: SUMMARY
+    icon: null
+    enabled: true
+  experience:
+    template: entry_list
+    title: EXPERIENCE
+    icon: experience
+    enabled: true
+  projects:
+    template: entry_list
+    title: PROJECTS
+    icon: projects
+    enabled: true
+  education:
+    template: entry_list
+    title: EDUCATION
+    icon: education
+    enabled: true
+  publications:
+    template: publications
+    title: PUBLICATIONS
+    icon: publications
+    style: apa           # apa, ieee or acm
+    enabled: true
+  skills:
+    template: simple_list
+    title: SKILLS
+    icon: skills
+    enabled: true
+  languages:
+    template: simple_list
+    title: LANGUAGES
+    icon: languages
+    enabled: true
+  certifications:
+    template: simple_list
+    title: CERTIFICATIONS
+    icon: certificate
+    enabled: true
+`
+
+const starterContentTemplate = `# Resume content for resume-builder.
+# Section keys must match the sections in the config.
+
+personal:
+  name: [[quote .Name]]
+  email: [[quote .Email]]
+  phone: [[quote .Phone]]
+  address: [[quote .Address]]
+[[- if .Website]]
+  website: [[quote .Website]]
+[[- end]]
+[[- if .GitHub]]
+  github: [[quote .GitHub]]
+[[- end]]
+[[- if .LinkedIn]]
+  linkedin: [[quote .LinkedIn]]
+[[- end]]
+
+# Printed three per row. content may use {{.Personal.<Field>}} placeholders;
+# set type: link and link to make the text clickable.
+contact_fields:
+  - field: address
+    content: "{{.Personal.Address}}"
+    icon: address
+  - field: email
+    content: "{{.Personal.Email}}"
+    icon: email
+  - field: phone
+    content: "{{.Personal.Phone}}"
+    icon: phone
+[[- if .Website]]
+  - field: website
+    content: Portfolio
+    icon: website
+    link: "{{.Personal.Website}}"
+    type: link
+[[- end]]
+[[- if .GitHub]]
+  - field: github
+    content: GitHub
+    icon: github
+    link: "{{.Personal.GitHub}}"
+    type: link
+[[- end]]
+[[- if .LinkedIn]]
+  - field: linkedin
+    content: LinkedIn
+    icon: linkedin
+    link: "{{.Personal.LinkedIn}}"
+    type: link
+[[- end]]
+
+sections:
+  # simple_list with a paragraph
+  summary:
+    content: One or two sentences about what you do and what you are looking for.
+
+  # entry_list with title/company entries
+  experience:
+    items:
+      - title: Job Title
+        company: Company
+        location: City, ST
+        start_date: Jan 2020
+        end_date: Present
+        description:
+          - What you built or improved, with a number if you have one
+          - Another accomplishment
+
+  # entry_list used for side projects; company names the project's context
+  projects:
+    items:
+      - title: Project Name
+        company: Open Source
+        start_date: Mar 2022
+        end_date: Present
+        description:
+          - What the project does and who uses it
+          - Your part in it
+
+  # entry_list with degree/institution entries
+  education:
+    items:
+      - degree: Degree
+        institution: School
+        location: City, ST
+        start_date: Sep 2015
+        end_date: Jun 2019
+
+  # simple_list with a list of strings
+  skills:
+    - Skill One
+    - Skill Two
+    - Skill Three
+
+  # simple_list of languages with your level in each
+  languages:
+    - English (native)
+    - Spanish (B2)
+
+  certifications:
+    - Certification Name
+
+  # publications are read from a BibTeX file. Uncomment and point bibtex
+  # at your .bib to list them.
+  # publications:
+  #   bibtex: publications.bib
+`
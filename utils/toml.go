@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// tomlLinePattern matches the first significant line of a TOML document:
+// a table header of bare keys, such as [personal] or [[contact_fields]],
+// or a key = value pair. A JSON array never matches, since its first line
+// holds a value or nothing after the bracket.
+var tomlLinePattern = regexp.MustCompile(`^(\[\[?\s*[A-Za-z0-9_-]+(\s*\.\s*[A-Za-z0-9_-]+)*\s*\]\]?\s*(#.*)?$|[A-Za-z0-9_."'-]+\s*=)`)
+
+// looksLikeTOML sniffs documents that have no file extension to go by
+func looksLikeTOML(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return tomlLinePattern.MatchString(line)
+	}
+	return false
+}
+
+// parseTOML decodes a TOML document into the value tree JSON would give
+// and records the position of every key
+func parseTOML(filename string, data []byte, positions map[string]Position) (interface{}, error) {
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, tomlProblem(filename, err)
+	}
+
+	scanner := tomlPositionScanner{arrayTables: make(map[string]int), positions: positions}
+	scanner.parser.Reset(data)
+	for scanner.parser.NextExpression() {
+		scanner.expression(scanner.parser.Expression())
+	}
+	if err := scanner.parser.Error(); err != nil {
+		return nil, tomlProblem(filename, err)
+	}
+
+	return tomlValue(doc), nil
+}
+
+// tomlValue converts decoded TOML values to their JSON equivalents: every
+// number becomes a float64 and dates and times become strings
+func tomlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = tomlValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = tomlValue(item)
+		}
+		return v
+	case int64:
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		// toml.LocalDate, LocalTime and LocalDateTime
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func tomlProblem(filename string, err error) error {
+	var decodeErr *toml.DecodeError
+	if errors.As(err, &decodeErr) {
+		line, column := decodeErr.Position()
+		return Problem{
+			Severity: SeverityError,
+			Code:     "syntax-error",
+			File:     filename,
+			Line:     line,
+			Column:   column,
+			Message:  strings.TrimPrefix(decodeErr.Error(), "toml: "),
+		}
+	}
+	return fmt.Errorf("%s: %w", filename, err)
+}
+
+// tomlPositionScanner walks the TOML syntax tree and records where each
+// value is, keyed by the same paths the JSON and YAML scanners use
+type tomlPositionScanner struct {
+	parser unstable.Parser
+	// table is the path key/value pairs currently land in
+	table string
+	// arrayTables counts the elements of each [[array.table]] so far
+	arrayTables map[string]int
+	positions   map[string]Position
+}
+
+func (s *tomlPositionScanner) expression(node *unstable.Node) {
+	switch node.Kind {
+	case unstable.Table:
+		s.table = s.keyPath("", node.Key())
+		s.record(s.table, node.Child())
+	case unstable.ArrayTable:
+		path := s.keyPath("", node.Key())
+		index := s.arrayTables[path]
+		s.arrayTables[path] = index + 1
+		s.table = indexPath(path, index)
+		s.record(path, node.Child())
+		s.record(s.table, node.Child())
+	case unstable.KeyValue:
+		s.keyValue(s.table, node)
+	}
+}
+
+func (s *tomlPositionScanner) keyValue(parent string, node *unstable.Node) {
+	path := s.keyPath(parent, node.Key())
+
+	value := node.Value()
+	if value.Raw.Length > 0 {
+		s.record(path, value)
+	} else {
+		s.record(path, value.Next())
+	}
+	s.value(path, value)
+}
+
+// value records the elements of arrays and inline tables
+func (s *tomlPositionScanner) value(path string, node *unstable.Node) {
+	switch node.Kind {
+	case unstable.Array:
+		index := 0
+		children := node.Children()
+		for children.Next() {
+			child := children.Node()
+			if child.Kind == unstable.Comment {
+				continue
+			}
+			childPath := indexPath(path, index)
+			if child.Raw.Length > 0 {
+				s.record(childPath, child)
+			}
+			s.value(childPath, child)
+			index++
+		}
+	case unstable.InlineTable:
+		children := node.Children()
+		for children.Next() {
+			if child := children.Node(); child.Kind == unstable.KeyValue {
+				s.keyValue(path, child)
+			}
+		}
+	}
+}
+
+// keyPath joins a dotted key onto parent. Parts that name an array of
+// tables point at its latest element, as in TOML itself.
+func (s *tomlPositionScanner) keyPath(parent string, key unstable.Iterator) string {
+	path := parent
+	for key.Next() {
+		path = joinPath(path, string(key.Node().Data))
+		if count, ok := s.arrayTables[path]; ok && !key.IsLast() {
+			path = indexPath(path, count-1)
+		}
+	}
+	return path
+}
+
+func (s *tomlPositionScanner) record(path string, node *unstable.Node) {
+	shape := s.parser.Shape(node.Raw)
+	s.positions[path] = Position{Line: shape.Start.Line, Column: shape.Start.Column}
+}
@@ -3,15 +3,18 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"log"
 	"os"
 	stdimage "image"
 	"image/png"
 	"path/filepath"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/johnfercher/maroto/v2/pkg/components/col"
 	"github.com/johnfercher/maroto/v2/pkg/components/image"
@@ -30,6 +33,7 @@ import (
 // Universal Config Structure
 type Config struct {
 	PDF              PDFSettings                    `json:"pdf"`
+	Text             TextSettings                   `json:"text,omitempty"`
 	Spacing          map[string]float64             `json:"spacing"`
 	Fonts            map[string]FontDefinition      `json:"fonts"`
 	Colors           map[string]string              `json:"colors"`
@@ -42,8 +46,21 @@ type PDFSettings struct {
 	PageSize        string  `json:"page_size"`
 	Margins         Margins `json:"margins"`
 	BackgroundColor string  `json:"background_color"`
+	PDFA            bool    `json:"pdfa,omitempty"`          // write PDF/A-2b: embedded fonts, XMP metadata and an sRGB output intent
+	Title           string  `json:"title,omitempty"`         // document title, with content placeholders; default "<name> - Resume"
+	Subject         string  `json:"subject,omitempty"`       // document subject, with content placeholders; default "Resume"
+	CreationDate    string  `json:"creation_date,omitempty"` // fixed creation date, YYYY-MM-DD or RFC 3339; default the build time
 }
 
+// TextSettings lays out the plain-text output
+type TextSettings struct {
+	Width      int `json:"width,omitempty"`       // column lines wrap at, default 80
+	LabelWidth int `json:"label_width,omitempty"` // width of the contact label column, default fits the longest label
+}
+
+// DefaultTextWidth is the plain-text line width when the config sets none
+const DefaultTextWidth = 80
+
 type Margins struct {
 	Top    float64 `json:"top"`
 	Bottom float64 `json:"bottom"`
@@ -79,6 +96,7 @@ type SectionConfig struct {
 	Title    string  `json:"title,omitempty"`
 	Icon     *string `json:"icon"`
 	Enabled  bool    `json:"enabled"`
+	Style    string  `json:"style,omitempty"` // citation style of a publications section
 }
 
 // Content Structure
@@ -86,6 +104,19 @@ type Content struct {
 	Personal      PersonalInfo           `json:"personal"`
 	ContactFields []ContactField         `json:"contact_fields"`
 	Sections      map[string]interface{} `json:"sections"`
+
+	// Dir is the directory of the content file, which file names in the
+	// content are relative to. It is empty for stdin.
+	Dir string `json:"-"`
+}
+
+// ResolvePath resolves a file name written in the content against the
+// content file's directory
+func (c *Content) ResolvePath(name string) string {
+	if c.Dir == "" || name == Stdio || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(c.Dir, name)
 }
 
 type PersonalInfo struct {
@@ -127,26 +158,164 @@ type EntryItem struct {
 }
 
 // Universal helper functions
+
+// Stdio is the file name that stands for stdin when reading and stdout when writing
+const Stdio = "-"
+
+// DisplayName is how a file name appears in messages
+func DisplayName(filename string) string {
+	if filename == Stdio {
+		return "<stdin>"
+	}
+	return filename
+}
+
+// ReadInput reads a file, or stdin when filename is Stdio
+func ReadInput(filename string) ([]byte, error) {
+	if filename == Stdio {
+		return io.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(filename)
+}
+
+// LoadConfig reads a JSON, YAML or TOML config file
 func LoadConfig(filename string) (*Config, error) {
-	data, err := ioutil.ReadFile(filename)
+	config, _, err := LoadConfigSource(filename)
+	return config, err
+}
+
+// LoadConfigSource reads a config file and keeps its source positions
+func LoadConfigSource(filename string) (*Config, *Source, error) {
+	return loadConfigSource(filename, nil)
+}
+
+func loadConfigSource(filename string, overrides map[string]interface{}) (*Config, *Source, error) {
+	data, err := ReadInput(filename)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	src, err := ParseSource(DisplayName(filename), data)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, path := range SortedKeys(overrides) {
+		if err := src.Set(path, overrides[path]); err != nil {
+			return nil, nil, fmt.Errorf("override %s: %w", path, err)
+		}
 	}
 
 	var config Config
-	err = json.Unmarshal(data, &config)
-	return &config, err
+	err = src.Decode(&config)
+	return &config, src, err
 }
 
+// LoadContent reads a JSON, YAML or TOML content file
 func LoadContent(filename string) (*Content, error) {
-	data, err := ioutil.ReadFile(filename)
+	content, _, err := LoadContentSource(filename)
+	return content, err
+}
+
+// LoadContentSource reads a content file and keeps its source positions
+func LoadContentSource(filename string) (*Content, *Source, error) {
+	data, err := ReadInput(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, src, err := parseContent(filename, data)
+	if content != nil && filename != Stdio {
+		content.Dir = filepath.Dir(filename)
+	}
+	return content, src, err
+}
+
+func parseContent(filename string, data []byte) (*Content, *Source, error) {
+	if DetectFormat(filename, data) == FormatMarkdown {
+		return markdownContent(DisplayName(filename), data)
+	}
+
+	src, err := ParseSource(DisplayName(filename), data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// JSON Resume documents can be used directly and are converted on load
+	if IsJSONResume(src.Value) {
+		return jsonResumeContent(src)
 	}
 
 	var content Content
-	err = json.Unmarshal(data, &content)
-	return &content, err
+	err = src.Decode(&content)
+	return &content, src, err
+}
+
+// SaveJSON writes v as indented JSON, or to stdout when filename is Stdio.
+// Existing files are only replaced with force set.
+func SaveJSON(filename string, v interface{}, force bool) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if filename == Stdio {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if !force {
+		if _, err := os.Stat(filename); err == nil {
+			return fmt.Errorf("%s: %w", filename, os.ErrExist)
+		}
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// LoadInputs loads the config and content files every command works from
+// and validates them against each other. Validation failures are returned
+// as Problems.
+func LoadInputs(configFile, contentFile string) (*Config, *Content, error) {
+	return LoadInputsWithOverrides(configFile, contentFile, nil)
+}
+
+// LoadInputsWithOverrides is LoadInputs with config values replaced by
+// dotted path first, e.g. "colors.primary" or "sections.skills.enabled"
+func LoadInputsWithOverrides(configFile, contentFile string, overrides map[string]interface{}) (*Config, *Content, error) {
+	var problems Problems
+
+	config, configSrc, err := loadConfigSource(configFile, overrides)
+	if err != nil && !collectProblem(&problems, err) {
+		return nil, nil, fmt.Errorf("loading config %s: %w", DisplayName(configFile), err)
+	}
+
+	content, contentSrc, err := LoadContentSource(contentFile)
+	if err != nil && !collectProblem(&problems, err) {
+		return nil, nil, fmt.Errorf("loading content %s: %w", DisplayName(contentFile), err)
+	}
+
+	// Only cross-check files that parsed; type errors still leave a value
+	if configSrc != nil && contentSrc != nil {
+		problems = append(problems, Validate(config, configSrc, content, contentSrc)...)
+	}
+
+	errs, warnings := problems.sorted(DisplayName(configFile), DisplayName(contentFile)).split()
+	for _, warning := range warnings {
+		Warn(warning)
+	}
+	if len(errs) > 0 {
+		return config, content, errs
+	}
+	return config, content, nil
+}
+
+// collectProblem appends err if it is a located problem
+func collectProblem(problems *Problems, err error) bool {
+	var problem Problem
+	if errors.As(err, &problem) {
+		*problems = append(*problems, problem)
+		return true
+	}
+	return false
 }
 
 func HexToColor(hex string) props.Color {
@@ -186,18 +355,13 @@ func ResolveColor(colorName string, colors map[string]string) props.Color {
 
 func EnsureIconExists(iconName string, iconConfig *IconConfig, colors map[string]string) string {
 	// Always use colored icons with the configured color
-	colorHex := ""
-	if iconConfig.Color != "" {
-		if colorValue, exists := colors[iconConfig.Color]; exists {
-			colorHex = colorValue
-		} else {
-			colorHex = iconConfig.Color
-		}
-	}
+	colorHex := ResolveIconColor(iconConfig, colors)
 	
 	return EnsureColoredIconExists(iconName, iconConfig, colorHex)
 }
 
+var iconCacheMu sync.Mutex
+
 func EnsureColoredIconExists(iconName string, iconConfig *IconConfig, colorHex string) string {
 	if iconName == "" {
 		return ""
@@ -209,11 +373,11 @@ func EnsureColoredIconExists(iconName string, iconConfig *IconConfig, colorHex s
 	}
 	
 	// Always include color in filename
-	colorSuffix := strings.TrimPrefix(colorHex, "#")
-	if colorSuffix == "" {
-		colorSuffix = "000000" // default to black if no color specified
-	}
-	pngPath := filepath.Join(iconConfig.OutputDir, fmt.Sprintf("%s_%s_%dpx.png", iconFileName, colorSuffix, iconConfig.DefaultSize))
+	pngPath := iconPNGPath(iconFileName, iconConfig.OutputDir, iconConfig.DefaultSize, colorHex)
+	
+	// Concurrent builds share the cache, so only one converts at a time
+	iconCacheMu.Lock()
+	defer iconCacheMu.Unlock()
 	
 	// Return if PNG already exists
 	if _, err := os.Stat(pngPath); err == nil {
@@ -223,7 +387,7 @@ func EnsureColoredIconExists(iconName string, iconConfig *IconConfig, colorHex s
 	// Convert from SVG with optional color
 	err := convertColoredIcon(iconFileName, iconConfig.SVGPaths, iconConfig.OutputDir, iconConfig.DefaultSize, colorHex)
 	if err != nil {
-		log.Printf("Could not convert icon %s: %v", iconName, err)
+		Warn(Problem{Code: "icon-conversion-failed", Icon: iconName, Message: fmt.Sprintf("could not convert icon: %v", err)})
 		return ""
 	}
 	
@@ -240,11 +404,7 @@ func convertColoredIcon(iconName string, svgPaths []string, outputDir string, si
 	for _, svgPath := range svgPaths {
 		fullSvgPath := filepath.Join(svgPath, iconName+".svg")
 		if _, err := os.Stat(fullSvgPath); err == nil {
-			colorSuffix := strings.TrimPrefix(colorHex, "#")
-			if colorSuffix == "" {
-				colorSuffix = "000000"
-			}
-			outputPath := filepath.Join(outputDir, fmt.Sprintf("%s_%s_%dpx.png", iconName, colorSuffix, size))
+			outputPath := iconPNGPath(iconName, outputDir, size, colorHex)
 			return convertSVGToPNG(fullSvgPath, outputPath, size, colorHex)
 		}
 	}
@@ -345,6 +505,22 @@ func ResolveTemplate(templateStr string, content *Content) string {
 	return buf.String()
 }
 
+// ParseCreationDate reads the creation date setting: a day (2006-01-02) or
+// an RFC 3339 time. An empty setting gives the zero time.
+func ParseCreationDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return day, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("creation date %q is neither YYYY-MM-DD nor an RFC 3339 time", value)
+	}
+	return t, nil
+}
+
 func ResolveFontStyle(styleStr string) fontstyle.Type {
 	switch strings.ToLower(styleStr) {
 	case "bold":
@@ -377,7 +553,7 @@ func AddDivider(mrt core.Maroto, cfg *Config) error {
 func CreateColoredIcon(iconName string, cfg *Config, size int) core.Component {
 	iconPath := EnsureIconExists(iconName, &cfg.Icons, cfg.Colors)
 	if iconPath == "" {
-		log.Printf("Could not create icon for %s", iconName)
+		Warn(Problem{Code: "icon-unavailable", Icon: iconName, Message: "could not create icon; leaving it blank"})
 		// Return empty component if icon can't be created
 		return text.New("", props.Text{})
 	}
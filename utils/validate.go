@@ -0,0 +1,602 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// SectionOrder lists the body sections in the order templates render them
+var SectionOrder = []string{"summary", "experience", "projects", "education", "publications", "skills", "languages", "certifications"}
+
+// templateFields lists the SectionTemplate fields each template kind reads
+var templateFields = map[string][]string{
+	"header":       {"spacing", "font"},
+	"contact":      {"spacing", "font"},
+	"simple_list":  {"spacing", "font", "title_spacing"},
+	"entry_list":   {"font", "title_spacing", "item_spacing"},
+	"publications": {"font", "title_spacing", "item_spacing"},
+}
+
+// bodyTemplates are the section templates that can render a body section
+var bodyTemplates = map[string]bool{"simple_list": true, "entry_list": true, "publications": true}
+
+var entryItemFields = map[string]bool{
+	"title": true, "company": true, "institution": true, "degree": true,
+	"location": true, "start_date": true, "end_date": true, "skill": true,
+}
+
+var hexColorPattern = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+
+// minTextWidth is the narrowest plain-text column lines can wrap into
+const minTextWidth = 20
+
+type validator struct {
+	cfg        *Config
+	cfgSrc     *Source
+	content    *Content
+	contentSrc *Source
+	problems   Problems
+}
+
+// Validate checks every cross-reference between the config and content:
+// sections to templates, templates to fonts and spacing, fonts to colors,
+// icons to mappings, and the shape of each content section
+func Validate(cfg *Config, cfgSrc *Source, content *Content, contentSrc *Source) Problems {
+	v := &validator{cfg: cfg, cfgSrc: cfgSrc, content: content, contentSrc: contentSrc}
+
+	if cfgSrc != nil {
+		v.checkUnknownFields(cfgSrc, cfgSrc.Value, reflect.TypeOf(Config{}), "")
+	}
+	if contentSrc != nil {
+		v.checkUnknownFields(contentSrc, contentSrc.Value, reflect.TypeOf(Content{}), "")
+	}
+
+	v.checkColors()
+	v.checkFonts()
+	v.checkText()
+	v.checkPDF()
+	v.checkSectionTemplates()
+	v.checkSections()
+	v.checkIcons()
+	v.checkContactFields()
+	v.checkContentSections()
+
+	return v.problems.sorted(sourceFile(cfgSrc), sourceFile(contentSrc))
+}
+
+// report records an error at path. The returned problem can be adjusted
+// (severity, icon) before the next report.
+func (v *validator) report(src *Source, path, code, format string, args ...interface{}) *Problem {
+	problem := locatedProblem(src, path, fmt.Sprintf(format, args...))
+	problem.Code = code
+	problem.Section = sectionOf(path)
+	v.problems = append(v.problems, problem)
+	return &v.problems[len(v.problems)-1]
+}
+
+// unknown reports a reference to a missing key, suggesting the closest match
+func (v *validator) unknown(src *Source, path, kind, name string, candidates []string) *Problem {
+	code := "unknown-" + strings.ReplaceAll(kind, " ", "-")
+	if suggestion := closestMatch(name, candidates); suggestion != "" {
+		return v.report(src, path, code, "unknown %s %q (did you mean %q?)", kind, name, suggestion)
+	}
+	return v.report(src, path, code, "unknown %s %q", kind, name)
+}
+
+// sectionOf returns the section key a "sections.<key>..." path points into
+func sectionOf(path string) string {
+	if !strings.HasPrefix(path, "sections.") {
+		return ""
+	}
+	key := strings.TrimPrefix(path, "sections.")
+	if cut := strings.IndexAny(key, ".["); cut >= 0 {
+		key = key[:cut]
+	}
+	return key
+}
+
+func (v *validator) checkUnknownFields(src *Source, value interface{}, t reflect.Type, path string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := make(map[string]reflect.Type)
+		var names []string
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			fields[name] = t.Field(i).Type
+			names = append(names, name)
+		}
+		for _, key := range SortedKeys(object) {
+			fieldType, known := fields[key]
+			if !known {
+				v.unknown(src, joinPath(path, key), "field", key, names)
+				continue
+			}
+			v.checkUnknownFields(src, object[key], fieldType, joinPath(path, key))
+		}
+	case reflect.Map:
+		if object, ok := value.(map[string]interface{}); ok {
+			for _, key := range SortedKeys(object) {
+				v.checkUnknownFields(src, object[key], t.Elem(), joinPath(path, key))
+			}
+		}
+	case reflect.Slice:
+		if list, ok := value.([]interface{}); ok {
+			for i, item := range list {
+				v.checkUnknownFields(src, item, t.Elem(), indexPath(path, i))
+			}
+		}
+	}
+}
+
+func (v *validator) checkColors() {
+	for _, name := range SortedKeys(v.cfg.Colors) {
+		if !hexColorPattern.MatchString(v.cfg.Colors[name]) {
+			v.report(v.cfgSrc, "colors."+name, "invalid-color", "%q is not a #RRGGBB color", v.cfg.Colors[name])
+		}
+	}
+}
+
+// checkColorRef accepts either a named color or a literal hex value
+func (v *validator) checkColorRef(path, color string) {
+	if color == "" {
+		return
+	}
+	if _, ok := v.cfg.Colors[color]; ok || hexColorPattern.MatchString(color) {
+		return
+	}
+	v.unknown(v.cfgSrc, path, "color", color, SortedKeys(v.cfg.Colors))
+}
+
+func (v *validator) checkFonts() {
+	for _, name := range SortedKeys(v.cfg.Fonts) {
+		font := v.cfg.Fonts[name]
+		path := "fonts." + name
+		v.checkColorRef(path+".color", font.Color)
+		if font.Size <= 0 {
+			v.report(v.cfgSrc, path+".size", "invalid-font-size", "font size must be greater than zero")
+		}
+		switch strings.ToLower(font.Style) {
+		case "", "normal", "bold", "italic", "bolditalic":
+		default:
+			v.report(v.cfgSrc, path+".style", "invalid-font-style", "unknown font style %q (use Normal, Bold, Italic or BoldItalic)", font.Style)
+		}
+	}
+}
+
+// checkText keeps the plain-text columns wide enough to wrap into
+func (v *validator) checkText() {
+	text := v.cfg.Text
+	if text.Width != 0 && text.Width < minTextWidth {
+		v.report(v.cfgSrc, "text.width", "invalid-text-width", "text width must be at least %d columns", minTextWidth)
+		return
+	}
+	width := text.Width
+	if width == 0 {
+		width = DefaultTextWidth
+	}
+	// Contact values keep at least minTextWidth columns next to their labels
+	if text.LabelWidth < 0 || text.LabelWidth > width-minTextWidth {
+		v.report(v.cfgSrc, "text.label_width", "invalid-text-width", "label width must be between 0 and %d columns", max(width-minTextWidth, 0))
+	}
+}
+
+func (v *validator) checkPDF() {
+	v.checkContentTemplate(v.cfgSrc, "pdf.title", v.cfg.PDF.Title)
+	v.checkContentTemplate(v.cfgSrc, "pdf.subject", v.cfg.PDF.Subject)
+	if _, err := ParseCreationDate(v.cfg.PDF.CreationDate); err != nil {
+		v.report(v.cfgSrc, "pdf.creation_date", "invalid-date", "%v", err)
+	}
+}
+
+func (v *validator) checkFontRef(path, font string) {
+	if _, ok := v.cfg.Fonts[font]; !ok {
+		v.unknown(v.cfgSrc, path, "font", font, SortedKeys(v.cfg.Fonts))
+	}
+}
+
+func (v *validator) checkSpacingRef(path, spacing string) {
+	if _, ok := v.cfg.Spacing[spacing]; !ok {
+		v.unknown(v.cfgSrc, path, "spacing", spacing, SortedKeys(v.cfg.Spacing))
+	}
+}
+
+func (v *validator) checkSectionTemplates() {
+	for _, name := range SortedKeys(v.cfg.SectionTemplates) {
+		tmpl := v.cfg.SectionTemplates[name]
+		path := "section_templates." + name
+
+		values := map[string]string{
+			"spacing":       tmpl.Spacing,
+			"font":          tmpl.Font,
+			"title_spacing": tmpl.TitleSpacing,
+			"item_spacing":  tmpl.ItemSpacing,
+		}
+		required := make(map[string]bool)
+		for _, field := range templateFields[name] {
+			required[field] = true
+		}
+
+		for _, field := range []string{"spacing", "font", "title_spacing", "item_spacing"} {
+			value := values[field]
+			if value == "" {
+				if required[field] {
+					v.report(v.cfgSrc, path, "missing-template-field", "missing %s", field)
+				}
+				continue
+			}
+			if field == "font" {
+				v.checkFontRef(path+"."+field, value)
+			} else {
+				v.checkSpacingRef(path+"."+field, value)
+			}
+		}
+	}
+}
+
+func (v *validator) checkSections() {
+	templateNames := SortedKeys(v.cfg.SectionTemplates)
+	bodySections := make(map[string]bool)
+	for _, key := range SectionOrder {
+		bodySections[key] = true
+	}
+
+	usesTitles := false
+	for _, key := range SortedKeys(v.cfg.Sections) {
+		section := v.cfg.Sections[key]
+		path := "sections." + key
+
+		switch {
+		case key == "header" || key == "contact":
+			// The header and contact blocks always use the template of the same name
+			if _, ok := v.cfg.SectionTemplates[key]; !ok && section.Enabled {
+				v.report(v.cfgSrc, path, "missing-template", "section_templates.%s is required to render the %s", key, key)
+			}
+		case !bodySections[key]:
+			v.report(v.cfgSrc, path, "unrendered-section", "section %q is never rendered (known sections: %s)", key, strings.Join(SectionOrder, ", ")).Severity = SeverityWarning
+		}
+
+		if section.Template == "" {
+			v.report(v.cfgSrc, path, "missing-template", "missing template")
+		} else if _, ok := v.cfg.SectionTemplates[section.Template]; !ok {
+			v.unknown(v.cfgSrc, path+".template", "section template", section.Template, templateNames)
+		} else if bodySections[key] && !bodyTemplates[section.Template] {
+			v.report(v.cfgSrc, path+".template", "unsupported-template", "template %q can't render a body section (use simple_list, entry_list or publications)", section.Template)
+		}
+
+		if section.Style != "" && !isCitationStyle(section.Style) {
+			v.report(v.cfgSrc, path+".style", "invalid-citation-style", "unknown citation style %q (use %s)", section.Style, strings.Join(CitationStyles, ", "))
+		}
+
+		if section.Icon != nil && *section.Icon != "" {
+			v.checkIconRef(v.cfgSrc, path+".icon", *section.Icon)
+		}
+
+		if section.Enabled && bodySections[key] {
+			usesTitles = true
+		}
+	}
+
+	// Fonts, colors and spacing the templates look up by fixed name
+	_, hasHeaderFont := v.cfg.Fonts["header"]
+	_, hasTitleFont := v.cfg.Fonts["section_title"]
+	_, hasEmphasisFont := v.cfg.Fonts["emphasis"]
+	_, hasDividerSpacing := v.cfg.Spacing["medium"]
+	_, hasDividerColor := v.cfg.Colors["secondary"]
+	if v.cfg.Sections["header"].Enabled {
+		v.requireName("fonts", "font", "header", hasHeaderFont)
+	}
+	if usesTitles {
+		v.requireName("fonts", "font", "section_title", hasTitleFont)
+		v.requireName("fonts", "font", "emphasis", hasEmphasisFont)
+	}
+	if v.cfg.Sections["contact"].Enabled {
+		v.requireName("spacing", "spacing", "medium", hasDividerSpacing)
+		v.requireName("colors", "color", "secondary", hasDividerColor)
+	}
+}
+
+func (v *validator) requireName(path, kind, name string, exists bool) {
+	if !exists {
+		v.report(v.cfgSrc, path, "missing-required-name", "missing %s %q, which the templates look up by name", kind, name)
+	}
+}
+
+func (v *validator) checkIconRef(src *Source, path, icon string) {
+	if _, ok := v.cfg.Icons.Mappings[icon]; !ok {
+		v.unknown(src, path, "icon", icon, SortedKeys(v.cfg.Icons.Mappings)).Icon = icon
+	}
+}
+
+func (v *validator) checkIcons() {
+	v.checkColorRef("icons.color", v.cfg.Icons.Color)
+	if len(v.cfg.Icons.Mappings) > 0 && len(v.cfg.Icons.SVGPaths) == 0 && v.cfg.Icons.OutputDir == "" {
+		v.report(v.cfgSrc, "icons", "missing-icon-source", "icons need svg_paths or an output_dir with cached PNGs")
+	}
+}
+
+func (v *validator) checkContactFields() {
+	hasLinks := false
+	for i, field := range v.content.ContactFields {
+		path := indexPath("contact_fields", i)
+		if field.Icon != "" {
+			v.checkIconRef(v.contentSrc, path+".icon", field.Icon)
+		}
+		v.checkContentTemplate(v.contentSrc, path+".content", field.Content)
+
+		if field.Type != nil && *field.Type != "link" {
+			v.report(v.contentSrc, path+".type", "invalid-contact-type", "unknown contact type %q (only \"link\" is supported)", *field.Type)
+		}
+		if field.Type != nil && *field.Type == "link" {
+			hasLinks = true
+			if field.Link == nil || *field.Link == "" {
+				v.report(v.contentSrc, path, "missing-link", "link fields need a link")
+			} else {
+				v.checkContentTemplate(v.contentSrc, path+".link", *field.Link)
+			}
+		}
+	}
+
+	if hasLinks && v.cfg.Sections["contact"].Enabled {
+		_, hasLinkColor := v.cfg.Colors["link"]
+		v.requireName("colors", "color", "link", hasLinkColor)
+	}
+}
+
+// checkContentTemplate catches placeholders ResolveTemplate would print
+// verbatim, in the content or in config text resolved against it
+func (v *validator) checkContentTemplate(src *Source, path, text string) {
+	if !strings.Contains(text, "{{") {
+		return
+	}
+	tmpl, err := template.New("content").Option("missingkey=error").Parse(text)
+	if err != nil {
+		v.report(src, path, "invalid-placeholder", "invalid placeholder: %v", err)
+		return
+	}
+	if err := tmpl.Execute(io.Discard, v.content); err != nil {
+		v.report(src, path, "invalid-placeholder", "invalid placeholder: %v", err)
+	}
+}
+
+func (v *validator) checkContentSections() {
+	for _, key := range SortedKeys(v.content.Sections) {
+		path := "sections." + key
+		section, configured := v.cfg.Sections[key]
+		if !configured {
+			// Unconfigured content is skipped when rendering, so it only warrants a warning
+			v.unknown(v.contentSrc, path, "section", key, SortedKeys(v.cfg.Sections)).Severity = SeverityWarning
+			continue
+		}
+
+		data := v.content.Sections[key]
+		switch section.Template {
+		case "simple_list":
+			v.checkSimpleList(path, data)
+		case "entry_list":
+			v.checkEntryList(path, data)
+		case "publications":
+			v.checkPublications(path, data)
+		}
+	}
+}
+
+func (v *validator) checkSimpleList(path string, data interface{}) {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		text, ok := value["content"]
+		if !ok {
+			v.report(v.contentSrc, path, "invalid-section-shape", "simple_list sections need a content string or a list of strings")
+			return
+		}
+		if _, ok := text.(string); !ok {
+			v.report(v.contentSrc, path+".content", "type-mismatch", "expected a string, found %s", describeValue(text))
+		}
+		for _, key := range SortedKeys(value) {
+			if key != "content" {
+				v.report(v.contentSrc, joinPath(path, key), "unknown-field", "unknown field %q in simple_list section", key)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			if _, ok := item.(string); !ok {
+				v.report(v.contentSrc, indexPath(path, i), "type-mismatch", "expected a string, found %s", describeValue(item))
+			}
+		}
+	default:
+		v.report(v.contentSrc, path, "invalid-section-shape", "simple_list sections need a content string or a list of strings, found %s", describeValue(data))
+	}
+}
+
+func (v *validator) checkEntryList(path string, data interface{}) {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		v.report(v.contentSrc, path, "invalid-section-shape", "entry_list sections need an items list, found %s", describeValue(data))
+		return
+	}
+	for _, key := range SortedKeys(object) {
+		if key != "items" {
+			v.report(v.contentSrc, joinPath(path, key), "unknown-field", "unknown field %q in entry_list section", key)
+		}
+	}
+
+	items, ok := object["items"].([]interface{})
+	if !ok {
+		v.report(v.contentSrc, path+".items", "invalid-section-shape", "expected a list of entries, found %s", describeValue(object["items"]))
+		return
+	}
+
+	fieldNames := append(SortedKeys(entryItemFields), "description")
+	for i, item := range items {
+		itemPath := indexPath(path+".items", i)
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			v.report(v.contentSrc, itemPath, "type-mismatch", "expected an entry object, found %s", describeValue(item))
+			continue
+		}
+
+		for _, key := range SortedKeys(entry) {
+			fieldPath := joinPath(itemPath, key)
+			switch {
+			case key == "description":
+				lines, ok := entry[key].([]interface{})
+				if !ok {
+					v.report(v.contentSrc, fieldPath, "type-mismatch", "expected a list of strings, found %s", describeValue(entry[key]))
+					continue
+				}
+				for j, line := range lines {
+					if _, ok := line.(string); !ok {
+						v.report(v.contentSrc, indexPath(fieldPath, j), "type-mismatch", "expected a string, found %s", describeValue(line))
+					}
+				}
+			case entryItemFields[key]:
+				if _, ok := entry[key].(string); !ok {
+					v.report(v.contentSrc, fieldPath, "type-mismatch", "expected a string, found %s", describeValue(entry[key]))
+				}
+			default:
+				v.unknown(v.contentSrc, fieldPath, "entry field", key, fieldNames)
+			}
+		}
+
+		// The heading prints title and company, or degree and institution,
+		// and only when both halves are there
+		_, hasTitle := entry["title"]
+		_, hasCompany := entry["company"]
+		_, hasDegree := entry["degree"]
+		_, hasInstitution := entry["institution"]
+		if !(hasTitle && hasCompany) && !(hasDegree && hasInstitution) {
+			v.report(v.contentSrc, itemPath, "missing-entry-heading", "entry needs a title and company, or a degree and institution, to print a heading").Severity = SeverityWarning
+		}
+	}
+}
+
+func (v *validator) checkPublications(path string, data interface{}) {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		v.report(v.contentSrc, path, "invalid-section-shape", "publications sections need a bibtex file name, found %s", describeValue(data))
+		return
+	}
+	for _, key := range SortedKeys(object) {
+		switch key {
+		case "bibtex", "highlight":
+			if _, ok := object[key].(string); !ok {
+				v.report(v.contentSrc, joinPath(path, key), "type-mismatch", "expected a string, found %s", describeValue(object[key]))
+			}
+		default:
+			v.unknown(v.contentSrc, joinPath(path, key), "publications field", key, []string{"bibtex", "highlight"})
+		}
+	}
+
+	publications, ok := PublicationsData(data)
+	if !ok {
+		if _, present := object["bibtex"]; !present {
+			v.report(v.contentSrc, path, "missing-bibtex", "publications sections need a bibtex file name")
+		}
+		return
+	}
+
+	bibFile := v.content.ResolvePath(publications.BibTeX)
+	entries, err := LoadBibTeX(bibFile)
+	if problem, ok := err.(Problem); ok {
+		problem.Section = sectionOf(path)
+		v.problems = append(v.problems, problem)
+		return
+	}
+	if err != nil {
+		v.report(v.contentSrc, path+".bibtex", "missing-bibtex", "can't read %s: %v", bibFile, err)
+		return
+	}
+
+	// Citations without a title or year still print, so these are warnings
+	for _, entry := range entries {
+		for _, field := range []string{"title", "year"} {
+			if entry.Fields[field] == "" {
+				v.problems = append(v.problems, Problem{
+					Severity: SeverityWarning,
+					Code:     "incomplete-citation",
+					File:     DisplayName(bibFile),
+					Line:     entry.Line,
+					Section:  sectionOf(path),
+					Message:  fmt.Sprintf("%s has no %s", entry.Key, field),
+				})
+			}
+		}
+	}
+}
+
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nothing"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64, int, int64, uint64:
+		return "a number"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// closestMatch returns the candidate within a small edit distance of name
+func closestMatch(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// SortedKeys returns the keys of m in order, for output that has to be
+// the same every run
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"sort"
+	"strings"
+)
+
+// contactIcons are the SVGs the stock config maps each contact icon name
+// to. They let an import find the icon name a config actually uses.
+var contactIcons = map[string]string{
+	"address":  "house",
+	"email":    "envelope",
+	"phone":    "phone",
+	"website":  "arrow-up-right-from-square",
+	"globe":    "arrow-up-right-from-square",
+	"github":   "github",
+	"linkedin": "linkedin",
+}
+
+// vcardProperty is one content line of a card, as in
+// "TEL;TYPE=cell,pref:+1 555 0100"
+type vcardProperty struct {
+	name   string
+	params map[string][]string
+	value  string
+}
+
+// has reports whether the property has a parameter value, matching TYPE
+// values case-insensitively and treating PREF=1 as TYPE=pref
+func (p vcardProperty) has(param, value string) bool {
+	for _, v := range p.params[param] {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return param == "TYPE" && value == "pref" && len(p.params["PREF"]) > 0
+}
+
+// ImportVCard reads the personal info from the first card in a .vcf file
+// and generates its contact fields. Icons are named after the entries in
+// mappings that point at the stock SVGs, so the fields match the config.
+func ImportVCard(filename string, mappings map[string]string) (*Content, error) {
+	data, err := ReadInput(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	file := DisplayName(filename)
+	cards := parseVCards(string(data))
+	if len(cards) == 0 {
+		return nil, Problem{Severity: SeverityError, Code: "unsupported-format", File: file, Message: "no BEGIN:VCARD ... END:VCARD card found"}
+	}
+	if len(cards) > 1 {
+		Warn(Problem{Code: "not-imported", File: file, Message: fmt.Sprintf("the file holds %d cards; only the first was imported", len(cards))})
+	}
+	card := cards[0]
+
+	content := &Content{Sections: make(map[string]interface{})}
+	personal := &content.Personal
+	personal.Name = vcardName(card)
+	personal.Email = preferred(card, "EMAIL", "")
+	personal.Phone = strings.TrimPrefix(preferred(card, "TEL", "cell"), "tel:")
+	if adr := preferredProperty(card, "ADR", "home"); adr != nil {
+		parts := append(splitVCardValue(adr.value), make([]string, 7)...)
+		personal.Address = formatLocation(&JSONResumeLocation{
+			Address:     vcardUnescape(parts[2]),
+			City:        vcardUnescape(parts[3]),
+			Region:      vcardUnescape(parts[4]),
+			PostalCode:  vcardUnescape(parts[5]),
+			CountryCode: vcardUnescape(parts[6]),
+		})
+	}
+
+	// Links past the first of each kind only get a contact field
+	var extra []ContactField
+	for _, prop := range card {
+		if prop.name != "URL" && prop.name != "X-SOCIALPROFILE" {
+			continue
+		}
+		link := vcardUnescape(prop.value)
+		label := ""
+		if prop.name == "X-SOCIALPROFILE" && len(prop.params["TYPE"]) > 0 {
+			label = strings.ToLower(prop.params["TYPE"][0])
+		}
+
+		var slot *string
+		switch network := linkNetwork(link, label); network {
+		case "github":
+			slot = &personal.GitHub
+		case "linkedin":
+			slot = &personal.LinkedIn
+		case "website":
+			slot = &personal.Website
+		default:
+			extra = append(extra, linkField(network, displayURL(link), "globe", link))
+			continue
+		}
+		if *slot == "" {
+			*slot = link
+		} else if *slot != link {
+			extra = append(extra, linkField("website", displayURL(link), "globe", link))
+		}
+	}
+
+	content.ContactFields = append(personalContactFields(*personal), extra...)
+	for i := range content.ContactFields {
+		content.ContactFields[i].Icon = mappedIcon(mappings, content.ContactFields[i].Icon)
+	}
+	return content, nil
+}
+
+// mappedIcon returns the icon name the config uses for a stock contact
+// icon: the name itself when it is mapped, otherwise the first name mapped
+// to the same SVG. Unknown icons are left for validation to report.
+func mappedIcon(mappings map[string]string, icon string) string {
+	if _, ok := mappings[icon]; ok {
+		return icon
+	}
+	names := make([]string, 0, len(mappings))
+	for name := range mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if mappings[name] == contactIcons[icon] {
+			return name
+		}
+	}
+	return icon
+}
+
+// parseVCards splits a .vcf file into cards of unfolded properties
+func parseVCards(data string) [][]vcardProperty {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	// Folded lines continue the line before them
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var cards [][]vcardProperty
+	var card []vcardProperty
+	inCard := false
+	lines := strings.Split(data, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		// So do quoted-printable soft line breaks (vCard 2.1). Other
+		// values, such as base64 photos, may end in = themselves.
+		for strings.HasSuffix(line, "=") && isQuotedPrintable(line) && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "=") + strings.TrimSpace(lines[i])
+		}
+		if line == "" {
+			continue
+		}
+		prop, ok := parseVCardLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCARD"):
+			inCard, card = true, nil
+		case prop.name == "END" && strings.EqualFold(prop.value, "VCARD"):
+			if inCard {
+				cards = append(cards, card)
+			}
+			inCard = false
+		case inCard:
+			card = append(card, prop)
+		}
+	}
+	return cards
+}
+
+func parseVCardLine(line string) (vcardProperty, bool) {
+	// The value starts at the first colon outside a quoted parameter
+	colon := -1
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return vcardProperty{}, false
+	}
+
+	head := strings.Split(line[:colon], ";")
+	name := strings.ToUpper(head[0])
+	if _, after, found := strings.Cut(name, "."); found {
+		name = after // drop the group, as in "item1.URL"
+	}
+
+	prop := vcardProperty{name: name, params: make(map[string][]string), value: line[colon+1:]}
+	for _, param := range head[1:] {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			// vCard 2.1 writes bare types, as in "TEL;CELL:"
+			key, value = "TYPE", param
+		}
+		key = strings.ToUpper(key)
+		for _, v := range strings.Split(strings.Trim(value, `"`), ",") {
+			prop.params[key] = append(prop.params[key], strings.ToLower(v))
+		}
+	}
+
+	if prop.has("ENCODING", "quoted-printable") {
+		if decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(prop.value))); err == nil {
+			prop.value = string(decoded)
+		}
+	}
+	return prop, true
+}
+
+// isQuotedPrintable reports whether a content line's parameters declare
+// ENCODING=QUOTED-PRINTABLE
+func isQuotedPrintable(line string) bool {
+	head, _, found := strings.Cut(line, ":")
+	return found && strings.Contains(strings.ToUpper(head), "QUOTED-PRINTABLE")
+}
+
+// splitVCardValue splits a structured value such as ADR or N into its
+// components at the semicolons that aren't escaped
+func splitVCardValue(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ';':
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// vcardName prefers the formatted name and falls back to the structured
+// "Family;Given;Middle;Prefix;Suffix" one
+func vcardName(card []vcardProperty) string {
+	if name := preferred(card, "FN", ""); name != "" {
+		return name
+	}
+	if n := preferredProperty(card, "N", ""); n != nil {
+		parts := append(splitVCardValue(n.value), "", "")
+		return strings.TrimSpace(vcardUnescape(parts[1]) + " " + vcardUnescape(parts[0]))
+	}
+	return ""
+}
+
+// preferred returns the unescaped value of the preferred property called
+// name: the one marked pref, then one with the fallback type, then the first
+func preferred(card []vcardProperty, name, fallback string) string {
+	if prop := preferredProperty(card, name, fallback); prop != nil {
+		return vcardUnescape(prop.value)
+	}
+	return ""
+}
+
+func preferredProperty(card []vcardProperty, name, fallback string) *vcardProperty {
+	var first, typed *vcardProperty
+	for i := range card {
+		prop := &card[i]
+		if prop.name != name {
+			continue
+		}
+		if prop.has("TYPE", "pref") {
+			return prop
+		}
+		if first == nil {
+			first = prop
+		}
+		if typed == nil && fallback != "" && prop.has("TYPE", fallback) {
+			typed = prop
+		}
+	}
+	if typed != nil {
+		return typed
+	}
+	return first
+}
+
+var vcardEscapes = strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func vcardUnescape(value string) string {
+	return strings.TrimSpace(vcardEscapes.Replace(value))
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// FileWatcher polls files and directories for changes. Polling keeps it
+// portable; no OS-specific notification API is needed.
+type FileWatcher struct {
+	paths    []string
+	snapshot map[string]fileStamp
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewFileWatcher starts watching paths from their current state
+func NewFileWatcher(paths []string) *FileWatcher {
+	w := &FileWatcher{}
+	w.SetPaths(paths)
+	return w
+}
+
+// SetPaths replaces the watched paths and takes a fresh snapshot
+func (w *FileWatcher) SetPaths(paths []string) {
+	w.paths = paths
+	w.snapshot = scanPaths(paths)
+}
+
+// Changed rescans the watched paths and reports whether anything was
+// added, removed or modified since the last scan
+func (w *FileWatcher) Changed() bool {
+	current := scanPaths(w.paths)
+	changed := len(current) != len(w.snapshot)
+	if !changed {
+		for path, stamp := range current {
+			if previous, ok := w.snapshot[path]; !ok || previous != stamp {
+				changed = true
+				break
+			}
+		}
+	}
+	w.snapshot = current
+	return changed
+}
+
+// Watch polls every interval and calls onChange once the watched files
+// have stopped changing for the debounce period. It returns when ctx is done.
+func (w *FileWatcher) Watch(ctx context.Context, interval, debounce time.Duration, onChange func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if w.Changed() {
+				lastChange = now
+				continue
+			}
+			if !lastChange.IsZero() && now.Sub(lastChange) >= debounce {
+				lastChange = time.Time{}
+				onChange()
+			}
+		}
+	}
+}
+
+// WatchPaths lists what a build depends on: the config and content files,
+// the icon SVG directories and the .bib files of publications sections.
+// Fonts aren't watched: the config names families of the PDF core fonts,
+// and the Go fonts PDF/A and previews use are compiled in, so no font is
+// read from a file that could change. cfg and content may be nil when they
+// failed to load.
+func WatchPaths(configFile, contentFile string, cfg *Config, content *Content) []string {
+	paths := []string{configFile, contentFile}
+	if cfg != nil {
+		paths = append(paths, cfg.Icons.SVGPaths...)
+	}
+	if content != nil {
+		paths = append(paths, BibliographyFiles(content)...)
+	}
+	return paths
+}
+
+func scanPaths(paths []string) map[string]fileStamp {
+	snapshot := make(map[string]fileStamp)
+	for _, root := range paths {
+		filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			if !info.IsDir() {
+				snapshot[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+	}
+	return snapshot
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"resume-builder/utils"
+)
+
+const (
+	watchInterval = 300 * time.Millisecond
+	watchDebounce = 250 * time.Millisecond
+)
+
+// watchInputs runs build once, then again every time the config, content,
+// icon SVGs or bibliographies change, until interrupted. Failed builds are
+// reported and the watch keeps going.
+func watchInputs(inputs inputFlags, target string, build func(*utils.Config, *utils.Content) error) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rebuild := func() (*utils.Config, *utils.Content) {
+		start := time.Now()
+		cfg, content, err := inputs.load()
+		if err == nil {
+			err = build(cfg, content)
+		}
+
+		stamp := start.Format("15:04:05")
+		if err != nil {
+			fmt.Printf("[%s] Build failed\n", stamp)
+			reportError("Error", err)
+		} else {
+			fmt.Printf("[%s] Built %s in %s\n", stamp, target, time.Since(start).Round(time.Millisecond))
+		}
+		return cfg, content
+	}
+
+	cfg, content := rebuild()
+	watcher := utils.NewFileWatcher(utils.WatchPaths(*inputs.config, *inputs.input, cfg, content))
+	fmt.Println("Watching for changes (Ctrl+C to stop)")
+
+	watcher.Watch(ctx, watchInterval, watchDebounce, func() {
+		cfg, content := rebuild()
+		watcher.SetPaths(utils.WatchPaths(*inputs.config, *inputs.input, cfg, content))
+	})
+
+	return exitOK
+}